@@ -0,0 +1,168 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Baseline records the findings of a lint run, so that when adopting the linter on existing dashboards only
+// new findings are reported.
+type Baseline struct {
+	Entries []BaselineEntry `json:"entries"`
+}
+
+// BaselineEntry identifies a single finding. The same finding may be recorded more than once, if a rule
+// reports it more than once.
+type BaselineEntry struct {
+	Rule string `json:"rule"`
+	// Dashboard is the UID of the dashboard, or its title if it has no UID.
+	Dashboard string `json:"dashboard"`
+	// Template and Annotation are the names of the template or annotation of the finding, if it is about one.
+	Template   string `json:"template,omitempty"`
+	Annotation string `json:"annotation,omitempty"`
+	Panel      *int   `json:"panel,omitempty"`
+	RefId      string `json:"refId,omitempty"`
+	// Fingerprint is a hash of the message of the finding, telling apart findings at the same location. Only the
+	// part of the message which comes from the rule is hashed, so renaming dashboards or panels, or reordering
+	// targets, keeps the finding in the baseline.
+	Fingerprint string `json:"fingerprint"`
+}
+
+// NewBaseline records all errors, warnings and infos of rs.
+func NewBaseline(rs *ResultSet) *Baseline {
+	b := &Baseline{Entries: []BaselineEntry{}}
+	for _, rc := range rs.results {
+		for _, r := range rc.Result.Results {
+			if isBaselined(r.Severity) {
+				b.Entries = append(b.Entries, newBaselineEntry(rc, r.Result))
+			}
+		}
+	}
+	sort.SliceStable(b.Entries, func(i, j int) bool {
+		return b.Entries[i].key() < b.Entries[j].key()
+	})
+	return b
+}
+
+func newBaselineEntry(rc ResultContext, r Result) BaselineEntry {
+	e := BaselineEntry{
+		Rule:        rc.Rule.Name(),
+		Fingerprint: fingerprintString(ruleMessage(rc, r.Message))[:16],
+	}
+	if rc.Dashboard != nil {
+		e.Dashboard = rc.Dashboard.UID
+		if e.Dashboard == "" {
+			e.Dashboard = rc.Dashboard.Title
+		}
+	}
+	if rc.Template != nil {
+		e.Template = rc.Template.Name
+	}
+	if rc.Annotation != nil {
+		e.Annotation = rc.Annotation.Name
+	}
+	if rc.Panel != nil {
+		id := rc.Panel.Id
+		e.Panel = &id
+	}
+	if rc.Target != nil {
+		e.RefId = rc.Target.RefId
+	}
+	return e
+}
+
+// isBaselined returns true if results with the given severity are recorded in a baseline.
+func isBaselined(s Severity) bool {
+	return s == Error || s == Warning || s == Info
+}
+
+func (e BaselineEntry) key() string {
+	panel := ""
+	if e.Panel != nil {
+		panel = fmt.Sprint(*e.Panel)
+	}
+	return strings.Join([]string{e.Rule, e.Dashboard, e.Template, e.Annotation, panel, e.RefId, e.Fingerprint}, "\x00")
+}
+
+func (e BaselineEntry) String() string {
+	s := fmt.Sprintf("rule '%s', dashboard '%s'", e.Rule, e.Dashboard)
+	if e.Template != "" {
+		s += fmt.Sprintf(", template '%s'", e.Template)
+	}
+	if e.Annotation != "" {
+		s += fmt.Sprintf(", annotation '%s'", e.Annotation)
+	}
+	if e.Panel != nil {
+		s += fmt.Sprintf(", panel id '%d'", *e.Panel)
+	}
+	if e.RefId != "" {
+		s += fmt.Sprintf(", target '%s'", e.RefId)
+	}
+	return s + fmt.Sprintf(", fingerprint '%s'", e.Fingerprint)
+}
+
+// Load reads the baseline from path.
+func (b *Baseline) Load(path string) error {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(buf, b); err != nil {
+		return fmt.Errorf("invalid baseline %s: %w", path, err)
+	}
+	return nil
+}
+
+// Write writes the baseline to w as a JSON document.
+func (b *Baseline) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(b)
+}
+
+// ApplyBaseline excludes all results recorded in b, so only new findings are reported. It returns the entries of
+// b which are not found anymore, as they were fixed. Entries are only checked for the dashboards and rules in rs,
+// so linting some of the dashboards, or running some of the rules, doesn't report the others as fixed.
+func (rs *ResultSet) ApplyBaseline(b *Baseline) []BaselineEntry {
+	known := make(map[string]int)
+	for _, e := range b.Entries {
+		known[e.key()]++
+	}
+
+	linted := make(map[string]bool)
+	for j := range rs.results {
+		rc := &rs.results[j]
+		e := newBaselineEntry(*rc, Result{})
+		linted[e.Rule+"\x00"+e.Dashboard] = true
+		for i, r := range rc.Result.Results {
+			if !isBaselined(r.Severity) {
+				continue
+			}
+			key := newBaselineEntry(*rc, r.Result).key()
+			if known[key] == 0 {
+				continue
+			}
+			known[key]--
+			rc.Result.Results[i].Severity = Exclude
+			rc.Result.Results[i].Message += " (Baseline)"
+			if rc.Reason == "" {
+				rc.Reason = "recorded in the baseline"
+			}
+		}
+	}
+
+	var fixed []BaselineEntry
+	for _, e := range b.Entries {
+		key := e.key()
+		if known[key] == 0 || !linted[e.Rule+"\x00"+e.Dashboard] {
+			continue
+		}
+		known[key]--
+		fixed = append(fixed, e)
+	}
+	return fixed
+}
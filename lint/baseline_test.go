@@ -0,0 +1,123 @@
+package lint
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBaseline(t *testing.T) {
+	newResults := func(panels ...int) *ResultSet {
+		rs := &ResultSet{}
+		for _, panel := range panels {
+			rc := newResultContext("rule1", "dash1", "panel", "", Error)
+			rc.Dashboard.UID = "uid1"
+			rc.Panel.Id = panel
+			rs.AddResult(rc)
+		}
+		rs.AddResult(newResultContext("rule2", "dash1", "", "", Success))
+		return rs
+	}
+
+	baseline := NewBaseline(newResults(2, 1, 1))
+	one, two := 1, 2
+	require.Equal(t, []BaselineEntry{
+		{Rule: "rule1", Dashboard: "uid1", Panel: &one, Fingerprint: fingerprintString("foo")[:16]},
+		{Rule: "rule1", Dashboard: "uid1", Panel: &one, Fingerprint: fingerprintString("foo")[:16]},
+		{Rule: "rule1", Dashboard: "uid1", Panel: &two, Fingerprint: fingerprintString("foo")[:16]},
+	}, baseline.Entries)
+
+	t.Run("Should round trip", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "baseline.json")
+		var buf bytes.Buffer
+		require.NoError(t, baseline.Write(&buf))
+		require.NoError(t, os.WriteFile(path, buf.Bytes(), 0600))
+
+		loaded := &Baseline{}
+		require.NoError(t, loaded.Load(path))
+		require.Equal(t, baseline, loaded)
+	})
+
+	t.Run("Should exclude findings in the baseline", func(t *testing.T) {
+		rs := newResults(1, 2)
+		fixed := rs.ApplyBaseline(baseline)
+		require.Equal(t, baseline.Entries[:1], fixed)
+		require.Equal(t, 0, rs.Count(Error))
+		for _, rc := range rs.results[:2] {
+			require.Equal(t, Exclude, rc.Result.Results[0].Severity)
+			require.Equal(t, "foo (Baseline)", rc.Result.Results[0].Message)
+			require.Equal(t, "recorded in the baseline", rc.Reason)
+		}
+	})
+
+	t.Run("Should report new findings", func(t *testing.T) {
+		rs := newResults(1, 1, 1, 2, 3)
+		require.Empty(t, rs.ApplyBaseline(baseline))
+		require.Equal(t, 2, rs.Count(Error))
+	})
+
+	t.Run("Should only report fixed entries of linted dashboards and rules", func(t *testing.T) {
+		rs := &ResultSet{}
+		rs.AddResult(newResultContext("rule1", "dash2", "panel1", "", Success))
+		rs.AddResult(newResultContext("rule2", "uid1", "", "", Success))
+		require.Empty(t, rs.ApplyBaseline(baseline))
+	})
+
+	t.Run("Should use the title of dashboards without a UID", func(t *testing.T) {
+		rs := &ResultSet{}
+		rs.AddResult(newResultContext("rule1", "dash1", "", "", Warning))
+		require.Equal(t, "dash1", NewBaseline(rs).Entries[0].Dashboard)
+	})
+
+	t.Run("Should match findings of renamed panels and reordered targets", func(t *testing.T) {
+		lintDashboard := func(panel string, targets string) *ResultSet {
+			d, err := NewDashboard([]byte(fmt.Sprintf(`{
+  "uid": "uid1",
+  "title": "dashboard",
+  "templating": {"list": [{"type": "datasource", "name": "datasource", "query": "prometheus"}]},
+  "panels": [{"id": 1, "title": %q, "type": "timeseries", "targets": [%s]}]
+}`, panel, targets)))
+			require.NoError(t, err)
+			rs := &ResultSet{}
+			NewTargetPromQLRule().Lint(d, rs)
+			return rs
+		}
+
+		baseline := NewBaseline(lintDashboard("CPU", `{"refId": "A", "expr": "sum(rate(foo[5m]"}`))
+		require.Len(t, baseline.Entries, 1)
+
+		rs := lintDashboard("CPU usage", `{"refId": "B", "expr": "up"}, {"refId": "A", "expr": "sum(rate(foo[5m]"}`)
+		require.Empty(t, rs.ApplyBaseline(baseline))
+		require.Equal(t, 0, rs.Count(Error))
+		require.Equal(t, 1, rs.Count(Exclude))
+	})
+
+	t.Run("Should tell apart findings of different templates", func(t *testing.T) {
+		lintDashboard := func(refreshA, refreshB int) *ResultSet {
+			d, err := NewDashboard([]byte(fmt.Sprintf(`{
+  "uid": "uid1",
+  "title": "dashboard",
+  "templating": {"list": [
+    {"type": "query", "name": "a", "query": "label_values(a)", "refresh": %d},
+    {"type": "query", "name": "b", "query": "label_values(b)", "refresh": %d}
+  ]}
+}`, refreshA, refreshB)))
+			require.NoError(t, err)
+			rs := &ResultSet{}
+			NewTemplateOnTimeRangeReloadRule().Lint(d, rs)
+			return rs
+		}
+
+		baseline := NewBaseline(lintDashboard(1, 2))
+		require.Len(t, baseline.Entries, 1)
+		require.Equal(t, "a", baseline.Entries[0].Template)
+
+		rs := lintDashboard(2, 1)
+		require.Equal(t, baseline.Entries, rs.ApplyBaseline(baseline))
+		require.Equal(t, 1, rs.Count(Error))
+	})
+}
@@ -1,52 +1,241 @@
 package lint
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
 	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	yaml "gopkg.in/yaml.v3"
 )
 
-// ConfigurationFile contains a map for rule exclusions, and warnings, where the key is the
-// rule name to be excluded or downgraded to a warning
+// ConfigurationFile contains a map for rule exclusions, warnings and severities, where the key is the
+// rule name to be excluded, downgraded to a warning or set to another severity, and the selection of rules to run.
 type ConfigurationFile struct {
+	// Extends are the built-in presets, or paths to other configuration files relative to this one, which this
+	// configuration is merged onto.
+	Extends    []string                             `yaml:"extends"`
+	Rules      RuleSelection                        `yaml:"rules"`
 	Exclusions map[string]*ConfigurationRuleEntries `yaml:"exclusions"`
 	Warnings   map[string]*ConfigurationRuleEntries `yaml:"warnings"`
-	Verbose    bool                                 `yaml:"-"`
-	Autofix    bool                                 `yaml:"-"`
+	Severities map[string]*ConfigurationRuleEntries `yaml:"severities"`
+	// RequiredMatchers are label matchers every selector of queries must have, each checked by rules like
+	// target-job-rule. The ones of job and instance replace the built-in rules.
+	RequiredMatchers []RequiredMatcher `yaml:"required_matchers"`
+	// CustomRules are rules declared in the configuration, run along with the built-in ones.
+	CustomRules []CustomRule `yaml:"custom_rules"`
+	Verbose     bool         `yaml:"-"`
+	Autofix     bool         `yaml:"-"`
+	// Now returns the current time, to tell whether entries have expired. It defaults to time.Now.
+	Now func() time.Time `yaml:"-"`
+
+	// applied and used record the rules the configuration was applied to, and the rules and entries which
+	// matched any problem, to find unused configuration.
+	applied map[string]bool
+	used    map[string]bool
+	// mu guards applied and used, as the configuration is applied to the results of dashboards linted
+	// concurrently.
+	mu sync.Mutex
+	// inherited are the rule selections of the configurations this one was merged onto.
+	inherited []RuleSelection
 }
 
 type ConfigurationRuleEntries struct {
-	Reason  string               `json:"reason,omitempty"`
-	Entries []ConfigurationEntry `json:"entries,omitempty"`
+	Reason string `json:"reason,omitempty" yaml:"reason,omitempty"`
+	// Severity is the severity to set the results of the rule to, only used for severities.
+	Severity string `json:"severity,omitempty" yaml:"severity,omitempty"`
+	// Expires, Owner and Ticket apply to all entries which don't set their own.
+	Expires string               `json:"expires,omitempty" yaml:"expires,omitempty"`
+	Owner   string               `json:"owner,omitempty" yaml:"owner,omitempty"`
+	Ticket  string               `json:"ticket,omitempty" yaml:"ticket,omitempty"`
+	Entries []ConfigurationEntry `json:"entries,omitempty" yaml:"entries,omitempty"`
+
+	// origins are the files the rule was configured in.
+	origins []string
+}
+
+// expiresLayout is the layout of the expiry dates of entries.
+const expiresLayout = "2006-01-02"
+
+// configSeverities are the severities which can be set in the severities of a configuration file.
+var configSeverities = map[string]Severity{
+	"error":   Error,
+	"warning": Warning,
+	"info":    Info,
+	"off":     Exclude,
 }
 
 // ConfigurationEntry will exist precisely once for every instance of a rule violation you wish
-// exclude or downgrade to a warning. Each ConfigurationEntry will have to be an *exact* match
-// to the combination of attributes set. Reason will not be evaluated, and is an opportunity for
-// the author to explain why the exception, or downgrade to warning exists.
+// exclude or downgrade to a warning. Each ConfigurationEntry will have to match the combination
+// of attributes set. Reason will not be evaluated, and is an opportunity for the author to explain
+// why the exception, or downgrade to warning exists.
+//
+// Titles, names, UIDs, refIds and files are patterns: a glob where * matches any characters, or a
+// regular expression if enclosed in slashes, e.g. /^Node (CPU|Memory)$/.
 type ConfigurationEntry struct {
-	Reason    string `json:"reason,omitempty"`
-	Dashboard string `json:"dashboard,omitempty"`
-	Panel     string `json:"panel,omitempty"`
+	Reason       string `json:"reason,omitempty" yaml:"reason,omitempty"`
+	Dashboard    string `json:"dashboard,omitempty" yaml:"dashboard,omitempty"`
+	DashboardUID string `json:"dashboardUid,omitempty" yaml:"dashboardUid,omitempty"`
+	Panel        string `json:"panel,omitempty" yaml:"panel,omitempty"`
+	// PanelId is a string for the same reason as TargetIdx.
+	PanelId string `json:"panelId,omitempty" yaml:"panelId,omitempty"`
+	// Template and Annotation are matched against the names of templates and annotations.
+	Template   string `json:"template,omitempty" yaml:"template,omitempty"`
+	Annotation string `json:"annotation,omitempty" yaml:"annotation,omitempty"`
 	// Alerts are currently included, so we can read in configuration for Mixtool.
-	Alert string `json:"alert,omitempty"`
+	Alert string `json:"alert,omitempty" yaml:"alert,omitempty"`
 	// This gets (un)marshalled as a string, because a 0 index is valid, but also the zero value of an int
-	TargetIdx string `json:"targetIdx"`
+	TargetIdx string `json:"targetIdx" yaml:"targetIdx,omitempty"`
+	// LegacyTargetIdx is the targetidx key configurations were written with before keys were case-sensitive. It
+	// is read as TargetIdx.
+	LegacyTargetIdx string `json:"-" yaml:"targetidx,omitempty"`
+	RefId           string `json:"refId,omitempty" yaml:"refId,omitempty"`
+	// File is matched against the path of the dashboard file relative to the directory of the configuration file
+	// the entry is in, or to the working directory if it wasn't loaded from a file, with forward slashes.
+	File string `json:"file,omitempty" yaml:"file,omitempty"`
+	// Severity overrides the severity set for the whole rule, only used for severities.
+	Severity string `json:"severity,omitempty" yaml:"severity,omitempty"`
+	// Expires is the date, as YYYY-MM-DD, after which the entry expires. Expired exclusions downgrade results
+	// to warnings instead, and expired warnings and severities stop applying.
+	Expires string `json:"expires,omitempty" yaml:"expires,omitempty"`
+	// Owner and Ticket are who is responsible for the entry, and where its removal is tracked. Like Reason,
+	// they are not evaluated, but reported with --verbose.
+	Owner  string `json:"owner,omitempty" yaml:"owner,omitempty"`
+	Ticket string `json:"ticket,omitempty" yaml:"ticket,omitempty"`
+
+	// origin and index are the file the entry was configured in, and its index within the rule there.
+	origin string
+	index  int
+	// dir is the absolute directory of origin, which File is relative to, if it is a file.
+	dir string
 }
 
 func (cre *ConfigurationRuleEntries) AddEntry(e ConfigurationEntry) {
 	cre.Entries = append(cre.Entries, e)
 }
 
+// match returns the first entry matching the result, or the rule itself as an entry if there are no entries at
+// all. Attributes not set on the entry fall back to the ones set for the rule.
+func (cre *ConfigurationRuleEntries) match(r ResultContext) (ConfigurationEntry, bool) {
+	if cre == nil {
+		return ConfigurationEntry{}, false
+	}
+	if len(cre.Entries) == 0 {
+		return cre.defaults(), true
+	}
+	for _, ce := range cre.Entries {
+		if ce.IsMatch(r) {
+			return ce.withDefaults(cre), true
+		}
+	}
+	return ConfigurationEntry{}, false
+}
+
+// defaults returns the attributes set for the whole rule, which apply to all entries not setting their own.
+func (cre *ConfigurationRuleEntries) defaults() ConfigurationEntry {
+	return ConfigurationEntry{
+		Reason:   cre.Reason,
+		Severity: cre.Severity,
+		Expires:  cre.Expires,
+		Owner:    cre.Owner,
+		Ticket:   cre.Ticket,
+	}
+}
+
+// setDefaults sets the attributes for the whole rule to the ones of ce.
+func (cre *ConfigurationRuleEntries) setDefaults(ce ConfigurationEntry) {
+	cre.Reason = ce.Reason
+	cre.Severity = ce.Severity
+	cre.Expires = ce.Expires
+	cre.Owner = ce.Owner
+	cre.Ticket = ce.Ticket
+}
+
+// withDefaults returns the entry with all attributes it doesn't set taken from the ones set for the rule.
+func (ce ConfigurationEntry) withDefaults(cre *ConfigurationRuleEntries) ConfigurationEntry {
+	defaults := cre.defaults()
+	for _, attr := range []struct{ value, fallback *string }{
+		{&ce.Reason, &defaults.Reason},
+		{&ce.Severity, &defaults.Severity},
+		{&ce.Expires, &defaults.Expires},
+		{&ce.Owner, &defaults.Owner},
+		{&ce.Ticket, &defaults.Ticket},
+	} {
+		if *attr.value == "" {
+			*attr.value = *attr.fallback
+		}
+	}
+	return ce
+}
+
+// severity returns the severity configured for the result, along with the entry setting it, if the result
+// matches any of the entries, or if there are no entries at all.
+func (cre *ConfigurationRuleEntries) severity(r ResultContext) (Severity, ConfigurationEntry, bool) {
+	ce, ok := cre.match(r)
+	if !ok {
+		return Success, ce, false
+	}
+	s, ok := configSeverities[ce.Severity]
+	return s, ce, ok
+}
+
+// String returns the attributes set on the entry.
+func (ce ConfigurationEntry) String() string {
+	var attrs []string
+	for _, attr := range []struct{ name, value string }{
+		{"dashboard", ce.Dashboard},
+		{"dashboardUid", ce.DashboardUID},
+		{"panel", ce.Panel},
+		{"panelId", ce.PanelId},
+		{"template", ce.Template},
+		{"annotation", ce.Annotation},
+		{"alert", ce.Alert},
+		{"targetIdx", ce.TargetIdx},
+		{"refId", ce.RefId},
+		{"file", ce.File},
+	} {
+		if attr.value != "" {
+			attrs = append(attrs, fmt.Sprintf("%s '%s'", attr.name, attr.value))
+		}
+	}
+	return "{" + strings.Join(attrs, ", ") + "}"
+}
+
 func (ce *ConfigurationEntry) IsMatch(r ResultContext) bool {
 	ret := true
-	if ce.Dashboard != "" && r.Dashboard != nil && ce.Dashboard != r.Dashboard.Title {
+	if ce.Dashboard != "" && r.Dashboard != nil && !matchPattern(ce.Dashboard, r.Dashboard.Title) {
+		ret = false
+	}
+
+	if ce.DashboardUID != "" && r.Dashboard != nil && !matchPattern(ce.DashboardUID, r.Dashboard.UID) {
 		ret = false
 	}
 
-	if ce.Panel != "" && r.Panel != nil && ce.Panel != r.Panel.Title {
+	if ce.Panel != "" && r.Panel != nil && !matchPattern(ce.Panel, r.Panel.Title) {
+		ret = false
+	}
+
+	if r.Panel != nil && ce.PanelId != "" {
+		id, err := strconv.Atoi(ce.PanelId)
+		if err == nil && id != r.Panel.Id {
+			ret = false
+		}
+	}
+
+	if ce.Template != "" && r.Template != nil && !matchPattern(ce.Template, r.Template.Name) {
+		ret = false
+	}
+
+	if ce.Annotation != "" && r.Annotation != nil && !matchPattern(ce.Annotation, r.Annotation.Name) {
 		ret = false
 	}
 
@@ -57,53 +246,154 @@ func (ce *ConfigurationEntry) IsMatch(r ResultContext) bool {
 		}
 	}
 
+	if ce.RefId != "" && r.Target != nil && !matchPattern(ce.RefId, r.Target.RefId) {
+		ret = false
+	}
+
+	if ce.File != "" && r.File != "" && !ce.matchFile(r.File) {
+		ret = false
+	}
+
 	return ret
 }
 
+// matchFile returns true if the File pattern of the entry matches file. Globs are matched against the cleaned path
+// of file relative to the directory of the entry, or its absolute path if they are absolute, so "./a.json",
+// "a.json" and the absolute path of a file all match the same way.
+func (ce *ConfigurationEntry) matchFile(file string) bool {
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return false
+	}
+	pattern := ce.File
+	if !isRegexpPattern(pattern) {
+		pattern = path.Clean(pattern)
+		if path.IsAbs(pattern) {
+			return matchPattern(pattern, filepath.ToSlash(abs))
+		}
+	}
+	dir := ce.dir
+	if dir == "" {
+		if dir, err = filepath.Abs("."); err != nil {
+			return false
+		}
+	}
+	rel, err := filepath.Rel(dir, abs)
+	if err != nil {
+		return false
+	}
+	return matchPattern(pattern, filepath.ToSlash(rel))
+}
+
+// isRegexpPattern returns true if pattern is a regular expression, rather than a glob.
+func isRegexpPattern(pattern string) bool {
+	return len(pattern) >= 2 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/")
+}
+
+// patterns caches the regular expressions compiled from the patterns of configuration entries.
+var patterns sync.Map
+
+// compilePattern compiles pattern to a regular expression. Patterns enclosed in slashes are regular
+// expressions, all others are globs where * matches any characters, and ? any single character.
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if re, ok := patterns.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	var expr string
+	if isRegexpPattern(pattern) {
+		expr = pattern[1 : len(pattern)-1]
+	} else {
+		expr = regexp.QuoteMeta(pattern)
+		expr = strings.ReplaceAll(expr, `\*`, ".*")
+		expr = strings.ReplaceAll(expr, `\?`, ".")
+		expr = "^" + expr + "$"
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	patterns.Store(pattern, re)
+	return re, nil
+}
+
+// matchPattern returns true if s matches pattern. Invalid patterns match nothing.
+func matchPattern(pattern, s string) bool {
+	re, err := compilePattern(pattern)
+	return err == nil && re.MatchString(s)
+}
+
 func (cf *ConfigurationFile) Apply(res ResultContext) ResultContext {
+	cf.markApplied(res)
+
 	{
 		exclusions, ok := cf.Exclusions[res.Rule.Name()]
-		matched := false
-		if exclusions != nil {
-			for _, ce := range exclusions.Entries {
-				if ce.IsMatch(res) {
-					matched = true
+		cf.markUsed("exclusions", exclusions, ok, res)
+		ce, matched := exclusions.match(res)
+		if matched || ok && exclusions == nil {
+			res = ce.annotate(res)
+			expired := cf.expired(ce)
+			for i, r := range res.Result.Results {
+				if !expired {
+					r.Severity = Exclude
+					r.Message += " (Excluded)"
+				} else if r.Severity == Error || r.Severity == Warning {
+					r.Severity = Warning
+					r.Message += fmt.Sprintf(" (Exclusion expired on %s)", ce.Expires)
 				}
+				res.Result.Results[i] = r
 			}
-			if len(exclusions.Entries) == 0 {
-				matched = true
-			}
-		} else if ok {
-			matched = true
 		}
-		if matched {
+	}
+
+	{
+		warnings, ok := cf.Warnings[res.Rule.Name()]
+		cf.markUsed("warnings", warnings, ok, res)
+		ce, matched := warnings.match(res)
+		if (matched || ok && warnings == nil) && !cf.expired(ce) {
+			res = ce.annotate(res)
 			for i, r := range res.Result.Results {
-				r.Severity = Exclude
-				r.Message += " (Excluded)"
+				r.Severity = Warning
 				res.Result.Results[i] = r
 			}
 		}
 	}
 
 	{
-		warnings, ok := cf.Warnings[res.Rule.Name()]
-		matched := false
-		if warnings != nil {
-			for _, ce := range warnings.Entries {
-				if ce.IsMatch(res) {
-					matched = true
+		severities, ok := cf.Severities[res.Rule.Name()]
+		cf.markUsed("severities", severities, ok, res)
+		severity, ce, ok := severities.severity(res)
+		if ok && !cf.expired(ce) {
+			res = ce.annotate(res)
+			for i, r := range res.Result.Results {
+				// Only problems are changed, so successes stay successes, and excluded results stay excluded
+				if r.Severity != Info && r.Severity != Warning && r.Severity != Error {
+					continue
 				}
+				if severity == Exclude {
+					r.Message += " (Excluded)"
+				}
+				r.Severity = severity
+				res.Result.Results[i] = r
 			}
-			if len(warnings.Entries) == 0 {
-				matched = true
-			}
-		} else if ok {
-			matched = true
 		}
-		if matched {
+	}
+
+	{
+		// Rules disabled inline in the dashboard take precedence, as they are the most specific
+		if s, ok := suppressionOf(res); ok {
+			excluded := false
 			for i, r := range res.Result.Results {
-				r.Severity = Warning
+				if r.Severity != Info && r.Severity != Warning && r.Severity != Error {
+					continue
+				}
+				r.Severity = Exclude
+				r.Message += fmt.Sprintf(" (Excluded by %s)", s.source)
 				res.Result.Results[i] = r
+				excluded = true
+			}
+			if excluded {
+				res.Reason, res.Owner, res.Ticket = s.reason, "", ""
+				res.Suppression = s.source
 			}
 		}
 	}
@@ -120,25 +410,353 @@ func (cf *ConfigurationFile) Apply(res ResultContext) ResultContext {
 	return res
 }
 
+// annotate records the reason, owner and ticket of the entry in res.
+func (ce ConfigurationEntry) annotate(res ResultContext) ResultContext {
+	res.Reason = ce.Reason
+	res.Owner = ce.Owner
+	res.Ticket = ce.Ticket
+	return res
+}
+
+// expired returns true if the entry has expired, i.e. its expiry date is before the current date.
+func (cf *ConfigurationFile) expired(ce ConfigurationEntry) bool {
+	if ce.Expires == "" {
+		return false
+	}
+	now := time.Now
+	if cf.Now != nil {
+		now = cf.Now
+	}
+	expires, err := time.ParseInLocation(expiresLayout, ce.Expires, time.Local)
+	if err != nil {
+		return false
+	}
+	return !now().Before(expires.AddDate(0, 0, 1))
+}
+
+// markApplied records that the configuration was applied to the results of a rule.
+func (cf *ConfigurationFile) markApplied(res ResultContext) {
+	cf.mu.Lock()
+	defer cf.mu.Unlock()
+	if cf.applied == nil {
+		cf.applied = map[string]bool{}
+	}
+	cf.applied[res.Rule.Name()] = true
+}
+
+// markUsed records the entries of a rule in section which match res, or the whole rule if it has no entries,
+// if res has any problem. ok is whether the rule is in section at all.
+func (cf *ConfigurationFile) markUsed(section string, cre *ConfigurationRuleEntries, ok bool, res ResultContext) {
+	if !ok || !hasProblems(res) {
+		return
+	}
+	cf.mu.Lock()
+	defer cf.mu.Unlock()
+	if cf.used == nil {
+		cf.used = map[string]bool{}
+	}
+	rule := res.Rule.Name()
+	if cre == nil || len(cre.Entries) == 0 {
+		for _, origin := range cre.fileOrigins() {
+			cf.used[usedKey(origin, section, rule, -1)] = true
+		}
+		return
+	}
+	for i, ce := range cre.Entries {
+		if ce.IsMatch(res) {
+			origin, index := ce.position(i)
+			cf.used[usedKey(origin, section, rule, -1)] = true
+			cf.used[usedKey(origin, section, rule, index)] = true
+		}
+	}
+}
+
+func usedKey(origin, section, rule string, entry int) string {
+	return fmt.Sprintf("%s\x00%s\x00%s\x00%d", origin, section, rule, entry)
+}
+
+// fileOrigins returns the files the rule was configured in, or a single unnamed one if it wasn't loaded from a file.
+func (cre *ConfigurationRuleEntries) fileOrigins() []string {
+	if cre == nil || len(cre.origins) == 0 {
+		return []string{""}
+	}
+	return cre.origins
+}
+
+// position returns the file the entry was configured in, and its index within the rule there. Entries which were
+// not loaded from a file are identified by their index i within the rule.
+func (ce ConfigurationEntry) position(i int) (string, int) {
+	if ce.origin == "" {
+		return "", i
+	}
+	return ce.origin, ce.index
+}
+
+// hasProblems returns true if any result of res is not a success.
+func hasProblems(res ResultContext) bool {
+	for _, r := range res.Result.Results {
+		if r.Severity != Success && r.Severity != Quiet {
+			return true
+		}
+	}
+	return false
+}
+
+// upgradeLegacyKeys moves the values of legacy keys of the entries to the keys replacing them.
+func (cf *ConfigurationFile) upgradeLegacyKeys() {
+	for _, section := range cf.sections() {
+		for _, cre := range section.rules {
+			if cre == nil {
+				continue
+			}
+			for i := range cre.Entries {
+				ce := &cre.Entries[i]
+				if ce.TargetIdx == "" {
+					ce.TargetIdx = ce.LegacyTargetIdx
+				}
+				ce.LegacyTargetIdx = ""
+			}
+		}
+	}
+}
+
+// configurationSection is a section of a configuration file, by the key it is configured under.
+type configurationSection struct {
+	name  string
+	rules map[string]*ConfigurationRuleEntries
+}
+
+func (cf *ConfigurationFile) sections() []configurationSection {
+	return []configurationSection{
+		{"exclusions", cf.Exclusions},
+		{"warnings", cf.Warnings},
+		{"severities", cf.Severities},
+	}
+}
+
+// UnusedConfiguration is a rule of a section of a configuration file, or a single entry of it, which matched no
+// problem, or a rule which doesn't exist.
+type UnusedConfiguration struct {
+	// File is the configuration file the rule or entry was configured in, if known.
+	File    string
+	Section string
+	Rule    string
+	// Entry is the entry which matched nothing, or nil if none of the rule did.
+	Entry   *ConfigurationEntry
+	Unknown bool
+}
+
+func (u UnusedConfiguration) String() string {
+	switch {
+	case u.Unknown:
+		return fmt.Sprintf("%s of unknown rule '%s'", u.Section, u.Rule)
+	case u.Entry != nil:
+		return fmt.Sprintf("%s entry %s of rule '%s' matched nothing", u.Section, u.Entry, u.Rule)
+	}
+	return fmt.Sprintf("%s of rule '%s' matched nothing", u.Section, u.Rule)
+}
+
+// AllRules returns all built-in rules along with the rules declared in the configuration: the rules of its
+// required matchers and its custom rules. Declared rules replace the built-in rules with the same name.
+func (cf *ConfigurationFile) AllRules() (RuleSet, error) {
+	rules := NewRuleSet()
+	declared, err := cf.declaredRules()
+	if err != nil {
+		return RuleSet{}, err
+	}
+	for _, rule := range declared {
+		rules.set(rule)
+	}
+	return rules, nil
+}
+
+// declaredRules returns the rules of the required matchers of the configuration, then its custom rules.
+func (cf *ConfigurationFile) declaredRules() ([]Rule, error) {
+	var rules []Rule
+	for _, m := range cf.RequiredMatchers {
+		matcherRules, err := NewRequiredMatcherRules(m)
+		if err != nil {
+			return nil, fmt.Errorf("invalid required matcher '%s': %w", m.Label, err)
+		}
+		rules = append(rules, matcherRules...)
+	}
+	for _, cr := range cf.CustomRules {
+		rule, err := NewCustomRule(cr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid custom rule '%s': %w", cr.Name, err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// Unused returns all rules and entries of the exclusions, warnings and severities which didn't match any problem
+// the configuration was applied to, and all rules which don't exist, other than rules of Mixtool. Rules which were not run are skipped, as
+// nothing is known about them.
+func (cf *ConfigurationFile) Unused() []UnusedConfiguration {
+	return UnusedConfigurations([]*ConfigurationFile{cf})
+}
+
+// UnusedConfigurations returns the unused rules and entries of all configs, like Unused. Rules and entries
+// loaded from the same file into several of the configs, e.g. from the configuration of a parent directory, are
+// only unused if they didn't match any problem in any of them.
+func UnusedConfigurations(configs []*ConfigurationFile) []UnusedConfiguration {
+	known := map[string]bool{}
+	rules := NewRuleSet()
+	for _, r := range rules.Rules() {
+		known[r.Name()] = true
+	}
+	used := map[string]bool{}
+	for _, cf := range configs {
+		// configurations are validated when loaded, so their rules can be declared
+		declared, _ := cf.declaredRules()
+		for _, r := range declared {
+			known[r.Name()] = true
+		}
+		cf.mu.Lock()
+		for key := range cf.used {
+			used[key] = true
+		}
+		cf.mu.Unlock()
+	}
+
+	type candidate struct {
+		UnusedConfiguration
+		section, index int
+	}
+	var candidates []candidate
+	seen := map[string]bool{}
+	add := func(u UnusedConfiguration, section, index int) {
+		key := usedKey(u.File, u.Section, u.Rule, index)
+		if u.Unknown {
+			key += "\x00unknown"
+		}
+		if seen[key] || used[key] {
+			return
+		}
+		seen[key] = true
+		candidates = append(candidates, candidate{u, section, index})
+	}
+
+	for _, cf := range configs {
+		for si, section := range cf.sections() {
+			for _, rule := range sortedKeys(section.rules) {
+				cre := section.rules[rule]
+				switch {
+				case !known[rule] && isAlertRule(cre):
+					continue
+				case !known[rule]:
+					for _, origin := range cre.fileOrigins() {
+						add(UnusedConfiguration{File: origin, Section: section.name, Rule: rule, Unknown: true}, si, -1)
+					}
+					continue
+				case !cf.applied[rule]:
+					continue
+				}
+				for _, origin := range cre.fileOrigins() {
+					add(UnusedConfiguration{File: origin, Section: section.name, Rule: rule}, si, -1)
+				}
+				if cre == nil {
+					continue
+				}
+				for i := range cre.Entries {
+					origin, index := cre.Entries[i].position(i)
+					// if the whole rule is unused, it is reported instead of each of its entries
+					if !used[usedKey(origin, section.name, rule, -1)] {
+						continue
+					}
+					add(UnusedConfiguration{File: origin, Section: section.name, Rule: rule, Entry: &cre.Entries[i]}, si, index)
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.section != b.section {
+			return a.section < b.section
+		}
+		if a.Rule != b.Rule {
+			return a.Rule < b.Rule
+		}
+		return a.index < b.index
+	})
+	unused := make([]UnusedConfiguration, 0, len(candidates))
+	for _, c := range candidates {
+		unused = append(unused, c.UnusedConfiguration)
+	}
+	return unused
+}
+
 func NewConfigurationFile() *ConfigurationFile {
 	return &ConfigurationFile{
 		Exclusions: map[string]*ConfigurationRuleEntries{},
 		Warnings:   map[string]*ConfigurationRuleEntries{},
+		Severities: map[string]*ConfigurationRuleEntries{},
 	}
 }
 
+// Load loads the configuration file at path, along with the configurations it extends, and merges it onto cf.
+// Missing files are ignored.
 func (cf *ConfigurationFile) Load(path string) error {
-	f, err := os.Open(path)
+	return cf.load(path, map[string]bool{})
+}
+
+// load loads the configuration file at path, where loading are the absolute paths of the files being loaded, to
+// detect cycles.
+func (cf *ConfigurationFile) load(path string, loading map[string]bool) error {
+	buf, err := os.ReadFile(path)
 	if err != nil && os.IsNotExist(err) {
 		return nil
 	} else if err != nil {
 		return err
 	}
-	defer f.Close()
 
-	dec := yaml.NewDecoder(f)
-	if err = dec.Decode(cf); err != nil {
-		return fmt.Errorf("could not unmarshal lint configuration %s: %w", path, err)
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	if loading[abs] {
+		return fmt.Errorf("%s is extended in a cycle", path)
+	}
+	loading[abs] = true
+	defer delete(loading, abs)
+
+	return cf.loadBytes(path, filepath.Dir(path), buf, loading)
+}
+
+// loadBytes loads the configuration named name from buf, resolving the files it extends relative to dir.
+func (cf *ConfigurationFile) loadBytes(name, dir string, buf []byte, loading map[string]bool) error {
+	var root yaml.Node
+	if err := yaml.Unmarshal(buf, &root); err != nil {
+		return fmt.Errorf("could not unmarshal lint configuration %s: %w", name, err)
+	}
+	f := NewConfigurationFile()
+	dec := yaml.NewDecoder(bytes.NewReader(buf))
+	dec.KnownFields(true)
+	if err := dec.Decode(f); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("could not unmarshal lint configuration %s: %w", name, decodeError(err))
+	}
+	f.upgradeLegacyKeys()
+	if err := f.validate(); err != nil {
+		return fmt.Errorf("invalid lint configuration %s: %w", name, withLine(&root, err))
+	}
+	if err := f.setOrigin(name, dir); err != nil {
+		return err
+	}
+
+	for _, extends := range f.Extends {
+		if err := cf.extend(extends, dir, loading); err != nil {
+			return fmt.Errorf("lint configuration %s: %w", name, err)
+		}
+	}
+	// rules can be custom rules of the configurations merged onto, so they are only known once these are loaded
+	if err := f.validateRules(cf); err != nil {
+		return fmt.Errorf("invalid lint configuration %s: %w", name, withLine(&root, err))
 	}
+	cf.merge(f)
 	return nil
 }
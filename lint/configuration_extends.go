@@ -0,0 +1,199 @@
+package lint
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// presets are the built-in configurations which can be extended by name.
+var presets = map[string]string{
+	// minimal only runs the rules checking that dashboards use a templated datasource, and valid queries.
+	"minimal": `
+rules:
+  only:
+  - template-datasource-rule
+  - panel-datasource-rule
+  - target-promql-rule
+  - target-logql-rule
+`,
+	// loki-only skips the rules about Prometheus conventions, for dashboards showing only logs.
+	"loki-only": `
+rules:
+  disable:
+  - template-job-rule
+  - template-instance-rule
+  - template-label-promql-rule
+  - target-promql-rule
+  - target-rate-interval-rule
+  - target-job-rule
+  - target-instance-rule
+  - target-counter-agg-rule
+`,
+	// mixin-strict runs every rule, as expected of the dashboards of monitoring mixins, even the ones disabled
+	// by the configurations it is merged onto.
+	"mixin-strict": `
+rules:
+  enable:
+  - "*"
+`,
+}
+
+// extend loads the preset or file named extends, relative to dir, and merges it onto cf.
+func (cf *ConfigurationFile) extend(extends, dir string, loading map[string]bool) error {
+	if preset, ok := presets[extends]; ok {
+		return cf.loadBytes(extends, "", []byte(preset), loading)
+	}
+	path := extends
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("can't extend '%s', it is neither a file nor one of the presets %s: %w", extends, strings.Join(sortedKeys(presets), ", "), err)
+	}
+	return cf.load(path, loading)
+}
+
+// LoadHierarchy loads the .lint files of dir and all of its parents, up to the root of the git repository dir is
+// in, or of the file system, and merges them onto cf. Files closer to dir take precedence.
+func (cf *ConfigurationFile) LoadHierarchy(dir string) error {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+
+	var dirs []string
+	for {
+		dirs = append(dirs, dir)
+		if _, err := os.Stat(filepath.Join(abs, ".git")); err == nil || filepath.Dir(abs) == abs {
+			break
+		}
+		abs = filepath.Dir(abs)
+		dir = filepath.Join(dir, "..")
+	}
+
+	for i := len(dirs) - 1; i >= 0; i-- {
+		if err := cf.Load(filepath.Join(dirs[i], ".lint")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Selections returns the rule selections of the configuration, in the order they are to be applied: the ones of
+// the configurations it was merged onto first, then its own.
+func (cf *ConfigurationFile) Selections() []RuleSelection {
+	return append(append([]RuleSelection{}, cf.inherited...), cf.Rules)
+}
+
+// setOrigin records that all rules and entries of the configuration were configured in origin, a file in dir if
+// dir is not empty.
+func (cf *ConfigurationFile) setOrigin(origin, dir string) error {
+	if dir != "" {
+		var err error
+		if dir, err = filepath.Abs(dir); err != nil {
+			return err
+		}
+	}
+	for _, section := range cf.sections() {
+		for rule, cre := range section.rules {
+			if cre == nil {
+				cre = &ConfigurationRuleEntries{}
+				section.rules[rule] = cre
+			}
+			cre.origins = []string{origin}
+			for i := range cre.Entries {
+				cre.Entries[i].origin = origin
+				cre.Entries[i].index = i
+				cre.Entries[i].dir = dir
+			}
+		}
+	}
+	return nil
+}
+
+// merge merges child, which is more specific, onto cf. The rule selections of child are applied after the ones of
+// cf. The exclusions, warnings and severities of a rule configured in both are combined: if child applies to the
+// whole rule, the combination does too, otherwise the entries of child are matched before the ones of cf, or before
+// cf applying to the whole rule.
+func (cf *ConfigurationFile) merge(child *ConfigurationFile) {
+	cf.inherited = append(append(cf.inherited, cf.Rules), child.inherited...)
+	cf.Rules = child.Rules
+	cf.Exclusions = mergeSection(cf.Exclusions, child.Exclusions)
+	cf.Warnings = mergeSection(cf.Warnings, child.Warnings)
+	cf.Severities = mergeSection(cf.Severities, child.Severities)
+	cf.RequiredMatchers = mergeDeclared(cf.RequiredMatchers, child.RequiredMatchers, func(m RequiredMatcher) string {
+		return m.Label
+	})
+	cf.CustomRules = mergeDeclared(cf.CustomRules, child.CustomRules, func(cr CustomRule) string {
+		return cr.Name
+	})
+}
+
+// mergeDeclared returns the required matchers or custom rules of parent, replaced by those of child with the same
+// key, followed by the others of child.
+func mergeDeclared[T any](parent, child []T, key func(T) string) []T {
+	merged := append([]T{}, parent...)
+	for _, c := range child {
+		i := slices.IndexFunc(merged, func(p T) bool { return key(p) == key(c) })
+		if i >= 0 {
+			merged[i] = c
+		} else {
+			merged = append(merged, c)
+		}
+	}
+	return merged
+}
+
+func mergeSection(parent, child map[string]*ConfigurationRuleEntries) map[string]*ConfigurationRuleEntries {
+	if parent == nil {
+		parent = map[string]*ConfigurationRuleEntries{}
+	}
+	for rule, c := range child {
+		if p, ok := parent[rule]; ok {
+			parent[rule] = mergeRuleEntries(p, c)
+		} else {
+			parent[rule] = c
+		}
+	}
+	return parent
+}
+
+func mergeRuleEntries(parent, child *ConfigurationRuleEntries) *ConfigurationRuleEntries {
+	if parent == nil {
+		parent = &ConfigurationRuleEntries{}
+	}
+	if child == nil {
+		child = &ConfigurationRuleEntries{}
+	}
+	merged := &ConfigurationRuleEntries{
+		origins: append(append([]string{}, parent.fileOrigins()...), child.fileOrigins()...),
+	}
+	switch {
+	case len(parent.Entries) == 0 && len(child.Entries) == 0:
+		merged.setDefaults(child.defaults().withDefaults(parent))
+	case len(child.Entries) == 0:
+		merged.setDefaults(child.defaults())
+	case len(parent.Entries) == 0:
+		// the parent applies to all results not matching any entry of the child, as an entry matching everything
+		for _, ce := range child.Entries {
+			merged.Entries = append(merged.Entries, ce.withDefaults(child))
+		}
+		for _, origin := range parent.fileOrigins() {
+			ce := parent.defaults()
+			ce.origin, ce.index = origin, -1
+			merged.Entries = append(merged.Entries, ce)
+		}
+	default:
+		// the entries keep the defaults of the rule they were configured for
+		for _, ce := range child.Entries {
+			merged.Entries = append(merged.Entries, ce.withDefaults(child))
+		}
+		for _, ce := range parent.Entries {
+			merged.Entries = append(merged.Entries, ce.withDefaults(parent))
+		}
+	}
+	return merged
+}
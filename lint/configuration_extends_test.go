@@ -0,0 +1,277 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfig(t *testing.T, path, config string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+	require.NoError(t, os.WriteFile(path, []byte(config), 0600))
+}
+
+func TestConfigurationLoadHierarchy(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(root, "repo"), 0700))
+	require.NoError(t, os.Mkdir(filepath.Join(root, "repo", ".git"), 0700))
+	writeConfig(t, filepath.Join(root, ".lint"), `
+exclusions:
+  panel-units-rule:
+`)
+	writeConfig(t, filepath.Join(root, "repo", ".lint"), `
+rules:
+  disable: [target-*]
+exclusions:
+  template-job-rule:
+    reason: No jobs
+`)
+	writeConfig(t, filepath.Join(root, "repo", "dashboards", "node", ".lint"), `
+rules:
+  enable: [target-promql-rule]
+warnings:
+  panel-units-rule:
+`)
+	dir := filepath.Join(root, "repo", "dashboards", "node")
+
+	config := NewConfigurationFile()
+	require.NoError(t, config.LoadHierarchy(dir))
+	require.Equal(t, []RuleSelection{
+		{},
+		{Disable: []string{"target-*"}},
+		{Enable: []string{"target-promql-rule"}},
+	}, config.Selections())
+	require.Equal(t, "No jobs", config.Exclusions["template-job-rule"].Reason)
+	require.Contains(t, config.Warnings, "panel-units-rule")
+	require.NotContains(t, config.Exclusions, "panel-units-rule", "should stop at the root of the repository")
+
+	t.Run("Should merge configurations of parent directories sharing unused entries", func(t *testing.T) {
+		other := NewConfigurationFile()
+		require.NoError(t, other.LoadHierarchy(filepath.Join(root, "repo", "dashboards")))
+		config.Apply(newResultContext("template-job-rule", "dash1", "", "", Error))
+		other.Apply(newResultContext("template-job-rule", "dash1", "", "", Success))
+		other.Apply(newResultContext("panel-units-rule", "dash1", "", "", Success))
+		config.Apply(newResultContext("panel-units-rule", "dash1", "", "", Success))
+
+		unused := UnusedConfigurations([]*ConfigurationFile{config, other})
+		require.Len(t, unused, 1)
+		require.Equal(t, filepath.Join(dir, ".lint"), unused[0].File)
+		require.Equal(t, "warnings of rule 'panel-units-rule' matched nothing", unused[0].String())
+	})
+}
+
+func TestConfigurationExtends(t *testing.T) {
+	dir := t.TempDir()
+	writeConfig(t, filepath.Join(dir, "shared", "base.yaml"), `
+exclusions:
+  template-job-rule:
+    entries:
+    - dashboard: Node
+`)
+	writeConfig(t, filepath.Join(dir, ".lint"), `
+extends: [loki-only, shared/base.yaml]
+rules:
+  enable: [target-promql-rule]
+exclusions:
+  template-job-rule:
+    entries:
+    - dashboard: Kubernetes
+`)
+
+	config := NewConfigurationFile()
+	require.NoError(t, config.Load(filepath.Join(dir, ".lint")))
+	selections := config.Selections()
+	require.Len(t, selections, 4)
+	require.Contains(t, selections[1].Disable, "target-promql-rule")
+	require.Equal(t, RuleSelection{Enable: []string{"target-promql-rule"}}, selections[3])
+
+	var dashboards []string
+	for _, ce := range config.Exclusions["template-job-rule"].Entries {
+		dashboards = append(dashboards, ce.Dashboard)
+	}
+	require.Equal(t, []string{"Kubernetes", "Node"}, dashboards)
+
+	t.Run("Should select the rules of presets", func(t *testing.T) {
+		rules := NewRuleSet()
+		selected, err := rules.Select(config.Selections()...)
+		require.NoError(t, err)
+		var names []string
+		for _, r := range selected.Rules() {
+			names = append(names, r.Name())
+		}
+		require.Contains(t, names, "target-promql-rule")
+		require.Contains(t, names, "target-logql-rule")
+		require.NotContains(t, names, "target-job-rule")
+	})
+
+	t.Run("Should reject unknown presets", func(t *testing.T) {
+		writeConfig(t, filepath.Join(dir, "unknown", ".lint"), "extends: [strict]\n")
+		err := NewConfigurationFile().Load(filepath.Join(dir, "unknown", ".lint"))
+		require.ErrorContains(t, err, "can't extend 'strict', it is neither a file nor one of the presets loki-only, minimal, mixin-strict")
+	})
+
+	t.Run("Should reject cycles", func(t *testing.T) {
+		writeConfig(t, filepath.Join(dir, "cycle", "a.yaml"), "extends: [b.yaml]\n")
+		writeConfig(t, filepath.Join(dir, "cycle", "b.yaml"), "extends: [../cycle/a.yaml]\n")
+		err := NewConfigurationFile().Load(filepath.Join(dir, "cycle", "a.yaml"))
+		require.ErrorContains(t, err, "is extended in a cycle")
+	})
+}
+
+func TestConfigurationLoadHierarchyEntries(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(root, ".git"), 0700))
+	writeConfig(t, filepath.Join(root, ".lint"), `
+exclusions:
+  panel-units-rule:
+    reason: parent
+warnings:
+  panel-title-description-rule:
+    reason: parent
+severities:
+  panel-datasource-rule:
+    severity: info
+    reason: parent
+`)
+	writeConfig(t, filepath.Join(root, "sub", ".lint"), `
+exclusions:
+  panel-units-rule:
+    entries:
+    - panel: CPU
+      reason: child
+warnings:
+  panel-title-description-rule:
+    entries:
+    - panel: CPU
+      reason: child
+severities:
+  panel-datasource-rule:
+    entries:
+    - panel: CPU
+      severity: error
+      reason: child
+`)
+	config := NewConfigurationFile()
+	require.NoError(t, config.LoadHierarchy(filepath.Join(root, "sub")))
+
+	for _, tc := range []struct {
+		rule     string
+		panel    string
+		severity Severity
+		reason   string
+	}{
+		{rule: "panel-units-rule", panel: "CPU", severity: Exclude, reason: "child"},
+		{rule: "panel-units-rule", panel: "Memory", severity: Exclude, reason: "parent"},
+		{rule: "panel-title-description-rule", panel: "CPU", severity: Warning, reason: "child"},
+		{rule: "panel-title-description-rule", panel: "Memory", severity: Warning, reason: "parent"},
+		{rule: "panel-datasource-rule", panel: "CPU", severity: Error, reason: "child"},
+		{rule: "panel-datasource-rule", panel: "Memory", severity: Info, reason: "parent"},
+	} {
+		t.Run(tc.rule+" "+tc.panel, func(t *testing.T) {
+			res := config.Apply(newResultContext(tc.rule, "dashboard", tc.panel, "", Error))
+			require.Equal(t, tc.severity, res.Result.Results[0].Severity)
+			require.Equal(t, tc.reason, res.Reason)
+		})
+	}
+	require.Empty(t, config.Unused())
+}
+
+func TestConfigurationMerge(t *testing.T) {
+	parent := &ConfigurationRuleEntries{
+		Reason: "parent",
+		Entries: []ConfigurationEntry{
+			{Panel: "panel1"},
+		},
+	}
+	child := &ConfigurationRuleEntries{
+		Owner: "team-a",
+		Entries: []ConfigurationEntry{
+			{Panel: "panel2", Reason: "child"},
+		},
+	}
+
+	t.Run("Should combine the entries, keeping the defaults of their rule", func(t *testing.T) {
+		merged := mergeRuleEntries(parent, child)
+		require.Equal(t, []ConfigurationEntry{
+			{Panel: "panel2", Reason: "child", Owner: "team-a"},
+			{Panel: "panel1", Reason: "parent"},
+		}, merged.Entries)
+	})
+
+	t.Run("Should apply to the whole rule if the child does", func(t *testing.T) {
+		merged := mergeRuleEntries(parent, &ConfigurationRuleEntries{Reason: "child"})
+		require.Empty(t, merged.Entries)
+		require.Equal(t, "child", merged.Reason)
+	})
+
+	t.Run("Should match the entries of the child before the parent applying to the whole rule", func(t *testing.T) {
+		merged := mergeRuleEntries(&ConfigurationRuleEntries{Reason: "parent", Severity: "info"}, child)
+		require.Equal(t, []ConfigurationEntry{
+			{Panel: "panel2", Reason: "child", Owner: "team-a"},
+			{Reason: "parent", Severity: "info", index: -1},
+		}, merged.Entries)
+
+		merged = mergeRuleEntries(nil, child)
+		require.Equal(t, []ConfigurationEntry{
+			{Panel: "panel2", Reason: "child", Owner: "team-a"},
+			{index: -1},
+		}, merged.Entries)
+	})
+
+	t.Run("Should fall back to the defaults of the parent", func(t *testing.T) {
+		merged := mergeRuleEntries(&ConfigurationRuleEntries{Reason: "parent", Ticket: "DASH-1"}, &ConfigurationRuleEntries{Reason: "child"})
+		require.Equal(t, "child", merged.Reason)
+		require.Equal(t, "DASH-1", merged.Ticket)
+	})
+}
+
+func TestConfigurationRequiredMatchers(t *testing.T) {
+	dir := t.TempDir()
+	writeConfig(t, filepath.Join(dir, "base.lint"), `
+required_matchers:
+  - label: job
+    operators: ["="]
+  - label: cluster
+`)
+	writeConfig(t, filepath.Join(dir, ".lint"), `
+extends: [base.lint]
+required_matchers:
+  - label: cluster
+    datasources: [prometheus, loki]
+exclusions:
+  template-cluster-rule:
+`)
+	config := NewConfigurationFile()
+	require.NoError(t, config.Load(filepath.Join(dir, ".lint")))
+	require.Equal(t, []RequiredMatcher{
+		{Label: "job", Operators: []string{"="}},
+		{Label: "cluster", Datasources: []string{Prometheus, Loki}},
+	}, config.RequiredMatchers, "should replace required matchers of the same label")
+
+	rules, err := config.AllRules()
+	require.NoError(t, err)
+	byName := map[string]Rule{}
+	var names []string
+	for _, r := range rules.Rules() {
+		byName[r.Name()] = r
+		names = append(names, r.Name())
+	}
+	builtin := NewRuleSet()
+	require.Len(t, names, len(builtin.Rules())+2, "should replace the built-in rules of job")
+	require.Equal(t, []string{Prometheus, Loki}, byName["target-cluster-rule"].Metadata().Datasources)
+
+	dashboard := Dashboard{
+		Title: "dashboard",
+		Templating: struct {
+			List []Template `json:"list"`
+		}{
+			List: []Template{{Type: "datasource", Query: Prometheus}},
+		},
+		Panels: []Panel{{Title: "panel", Type: "timeseries", Targets: []Target{{Expr: `up{job="$job", cluster=~"$cluster"}`}}}},
+	}
+	testRule(t, byName["target-job-rule"], dashboard, ResultSuccess)
+	testRule(t, byName["target-cluster-rule"], dashboard, ResultSuccess)
+}
@@ -0,0 +1,390 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	yaml "gopkg.in/yaml.v3"
+)
+
+func TestConfigurationLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".lint")
+	require.NoError(t, os.WriteFile(path, []byte(`
+rules:
+  only: [template-*, panel-*]
+  disable: [panel-units-rule]
+  enable: [target-promql-rule]
+exclusions:
+  template-job-rule:
+    reason: No jobs here
+`), 0600))
+
+	config := NewConfigurationFile()
+	require.NoError(t, config.Load(path))
+	require.Equal(t, RuleSelection{
+		Only:    []string{"template-*", "panel-*"},
+		Disable: []string{"panel-units-rule"},
+		Enable:  []string{"target-promql-rule"},
+	}, config.Rules)
+	require.Equal(t, "No jobs here", config.Exclusions["template-job-rule"].Reason)
+
+	t.Run("Should ignore missing files", func(t *testing.T) {
+		config := NewConfigurationFile()
+		require.NoError(t, config.Load(filepath.Join(t.TempDir(), ".lint")))
+		require.Equal(t, RuleSelection{}, config.Rules)
+	})
+}
+
+func TestConfigurationTemplateAndAnnotationEntries(t *testing.T) {
+	config := NewConfigurationFile()
+	require.NoError(t, yaml.Unmarshal([]byte(`
+exclusions:
+  test-template-rule:
+    entries:
+    - template: job
+      reason: Jobs are fixed
+  test-annotation-rule:
+    entries:
+    - dashboard: other
+      annotation: Deployments
+    - annotation: Annotations & Alerts
+`), config))
+
+	d := Dashboard{Title: "dash"}
+	for _, tc := range []struct {
+		desc     string
+		rc       ResultContext
+		expected Severity
+	}{
+		{
+			desc:     "Should exclude matching templates",
+			rc:       ResultContext{Rule: &TestRule{name: "test-template-rule"}, Dashboard: &d, Template: &Template{Name: "job"}},
+			expected: Exclude,
+		},
+		{
+			desc:     "Should not exclude other templates",
+			rc:       ResultContext{Rule: &TestRule{name: "test-template-rule"}, Dashboard: &d, Template: &Template{Name: "instance"}},
+			expected: Error,
+		},
+		{
+			desc:     "Should exclude matching annotations",
+			rc:       ResultContext{Rule: &TestRule{name: "test-annotation-rule"}, Dashboard: &d, Annotation: &Annotation{Name: "Annotations & Alerts"}},
+			expected: Exclude,
+		},
+		{
+			desc:     "Should not exclude annotations of other dashboards",
+			rc:       ResultContext{Rule: &TestRule{name: "test-annotation-rule"}, Dashboard: &d, Annotation: &Annotation{Name: "Deployments"}},
+			expected: Error,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			tc.rc.Result = RuleResults{Results: []FixableResult{{Result: Result{Severity: Error, Message: "foo"}}}}
+			require.Equal(t, tc.expected, config.Apply(tc.rc).Result.Results[0].Severity)
+		})
+	}
+}
+
+func TestConfigurationSeverities(t *testing.T) {
+	config := NewConfigurationFile()
+	require.NoError(t, yaml.Unmarshal([]byte(`
+severities:
+  rule1:
+    severity: info
+    reason: Only informational
+  rule2:
+    severity: error
+    entries:
+    - panel: panel1
+    - panel: panel2
+      severity: off
+      reason: Panel 2 is fine
+  rule3:
+    entries:
+    - dashboard: dash1
+      severity: warning
+`), config))
+	require.NoError(t, config.validate())
+
+	for _, tc := range []struct {
+		desc     string
+		rc       ResultContext
+		expected Result
+		reason   string
+	}{
+		{
+			desc:     "Should set the severity of the rule",
+			rc:       newResultContext("rule1", "dash1", "panel1", "", Error),
+			expected: Result{Severity: Info, Message: "foo"},
+			reason:   "Only informational",
+		},
+		{
+			desc:     "Should leave successes alone",
+			rc:       newResultContext("rule1", "dash1", "panel1", "", Success),
+			expected: Result{Severity: Quiet, Message: "foo"},
+			reason:   "Only informational",
+		},
+		{
+			desc:     "Should upgrade matching warnings",
+			rc:       newResultContext("rule2", "dash1", "panel1", "", Warning),
+			expected: Result{Severity: Error, Message: "foo"},
+		},
+		{
+			desc:     "Should turn off matching results",
+			rc:       newResultContext("rule2", "dash1", "panel2", "", Warning),
+			expected: Result{Severity: Exclude, Message: "foo (Excluded)"},
+			reason:   "Panel 2 is fine",
+		},
+		{
+			desc:     "Should leave results not matching any entry alone",
+			rc:       newResultContext("rule2", "dash1", "panel3", "", Warning),
+			expected: Result{Severity: Warning, Message: "foo"},
+		},
+		{
+			desc:     "Should use the severity of the entry",
+			rc:       newResultContext("rule3", "dash1", "", "", Error),
+			expected: Result{Severity: Warning, Message: "foo"},
+		},
+		{
+			desc:     "Should leave results of other rules alone",
+			rc:       newResultContext("rule4", "dash1", "", "", Error),
+			expected: Result{Severity: Error, Message: "foo"},
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			res := config.Apply(tc.rc)
+			require.Equal(t, tc.expected, res.Result.Results[0].Result)
+			require.Equal(t, tc.reason, res.Reason)
+		})
+	}
+
+	t.Run("Should reject invalid severities", func(t *testing.T) {
+		for _, tc := range []struct {
+			config string
+			err    string
+		}{
+			{
+				config: "severities:\n  rule1:\n    severity: fatal\n",
+				err:    "invalid severity 'fatal' for rule 'rule1', must be one of error, warning, info, off",
+			},
+			{
+				config: "severities:\n  rule1:\n    entries:\n    - panel: panel1\n      severity: hint\n",
+				err:    "invalid severity 'hint' for rule 'rule1', must be one of error, warning, info, off",
+			},
+			{
+				config: "severities:\n  rule1:\n    reason: Missing severity\n",
+				err:    "no severity set for rule 'rule1'",
+			},
+			{
+				config: "severities:\n  rule1:\n    entries:\n    - panel: panel1\n",
+				err:    "no severity set for an entry of rule 'rule1'",
+			},
+		} {
+			config := NewConfigurationFile()
+			require.NoError(t, yaml.Unmarshal([]byte(tc.config), config))
+			require.EqualError(t, config.validate(), tc.err)
+		}
+	})
+}
+
+func TestConfigurationEntryMatch(t *testing.T) {
+	rc := ResultContext{
+		Dashboard: &Dashboard{UID: "node-cpu", Title: "Node / CPU"},
+		Panel:     &Panel{Id: 4, Title: "CPU Usage"},
+		Target:    &Target{RefId: "B", Idx: 1},
+		File:      "dashboards/node/cpu.json",
+	}
+
+	for _, tc := range []struct {
+		desc     string
+		entry    ConfigurationEntry
+		expected bool
+	}{
+		{desc: "Should match the dashboard title", entry: ConfigurationEntry{Dashboard: "Node / CPU"}, expected: true},
+		{desc: "Should match a glob", entry: ConfigurationEntry{Dashboard: "Node *"}, expected: true},
+		{desc: "Should match a glob in full", entry: ConfigurationEntry{Dashboard: "Node"}, expected: false},
+		{desc: "Should match a ? glob", entry: ConfigurationEntry{Panel: "CPU Usag?"}, expected: true},
+		{desc: "Should match a regular expression", entry: ConfigurationEntry{Panel: "/^(CPU|Memory) /"}, expected: true},
+		{desc: "Should not match another regular expression", entry: ConfigurationEntry{Panel: "/^Memory/"}, expected: false},
+		{desc: "Should match the dashboard UID", entry: ConfigurationEntry{DashboardUID: "node-*"}, expected: true},
+		{desc: "Should not match another dashboard UID", entry: ConfigurationEntry{DashboardUID: "node"}, expected: false},
+		{desc: "Should match the panel id", entry: ConfigurationEntry{PanelId: "4"}, expected: true},
+		{desc: "Should not match another panel id", entry: ConfigurationEntry{PanelId: "5"}, expected: false},
+		{desc: "Should match the target refId", entry: ConfigurationEntry{RefId: "B"}, expected: true},
+		{desc: "Should not match another target refId", entry: ConfigurationEntry{RefId: "A"}, expected: false},
+		{desc: "Should match the file", entry: ConfigurationEntry{File: "dashboards/node/*.json"}, expected: true},
+		{desc: "Should not match another file", entry: ConfigurationEntry{File: "dashboards/k8s/*"}, expected: false},
+		{desc: "Should match a file with a ./ prefix", entry: ConfigurationEntry{File: "./dashboards/node/cpu.json"}, expected: true},
+		{desc: "Should match all attributes", entry: ConfigurationEntry{DashboardUID: "node-cpu", PanelId: "4", RefId: "B"}, expected: true},
+		{desc: "Should not match if any attribute differs", entry: ConfigurationEntry{DashboardUID: "node-cpu", PanelId: "4", RefId: "A"}, expected: false},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.entry.IsMatch(rc))
+		})
+	}
+
+	t.Run("Should match files however their path is given", func(t *testing.T) {
+		abs, err := filepath.Abs("dashboards/node/cpu.json")
+		require.NoError(t, err)
+		entry := ConfigurationEntry{File: "dashboards/node/*.json"}
+		for _, file := range []string{"./dashboards/node/cpu.json", "dashboards/../dashboards/node/cpu.json", abs} {
+			rc := rc
+			rc.File = file
+			require.True(t, entry.IsMatch(rc), file)
+		}
+		require.True(t, (&ConfigurationEntry{File: filepath.ToSlash(abs)}).IsMatch(rc))
+	})
+
+	t.Run("Should match files relative to the configuration file", func(t *testing.T) {
+		dir := t.TempDir()
+		writeConfig(t, filepath.Join(dir, "dashboards", ".lint"), `
+exclusions:
+  panel-units-rule:
+    entries:
+    - file: ./node/*.json
+`)
+		config := NewConfigurationFile()
+		require.NoError(t, config.Load(filepath.Join(dir, "dashboards", ".lint")))
+		entry := config.Exclusions["panel-units-rule"].Entries[0]
+
+		rc := rc
+		rc.File = filepath.Join(dir, "dashboards", "node", "cpu.json")
+		require.True(t, entry.IsMatch(rc))
+		rc.File = filepath.Join(dir, "node", "cpu.json")
+		require.False(t, entry.IsMatch(rc))
+	})
+
+	t.Run("Should reject invalid entries", func(t *testing.T) {
+		for _, tc := range []struct {
+			config string
+			err    string
+		}{
+			{
+				config: "exclusions:\n  rule1:\n    entries:\n    - panel: /(/\n",
+				err:    "invalid pattern '/(/': error parsing regexp: missing closing ): `(` in an entry of rule 'rule1'",
+			},
+			{
+				config: "warnings:\n  rule1:\n    entries:\n    - panelId: first\n",
+				err:    "invalid panelId 'first', must be a number in an entry of rule 'rule1'",
+			},
+		} {
+			config := NewConfigurationFile()
+			require.NoError(t, yaml.Unmarshal([]byte(tc.config), config))
+			require.EqualError(t, config.validate(), tc.err)
+		}
+	})
+}
+
+func TestConfigurationUnused(t *testing.T) {
+	config := NewConfigurationFile()
+	require.NoError(t, yaml.Unmarshal([]byte(`
+exclusions:
+  panel-units-rule:
+    entries:
+    - panel: panel1
+    - panel: panel2
+  panel-title-description-rule:
+  template-job-rule:
+  unknown-rule:
+warnings:
+  panel-datasource-rule:
+    entries:
+    - panel: panel1
+severities:
+  panel-no-targets-rule:
+    severity: info
+`), config))
+
+	config.Apply(newResultContext("panel-units-rule", "dash1", "panel1", "", Error))
+	config.Apply(newResultContext("panel-units-rule", "dash1", "panel2", "", Success))
+	config.Apply(newResultContext("panel-title-description-rule", "dash1", "panel1", "", Warning))
+	config.Apply(newResultContext("panel-datasource-rule", "dash1", "panel2", "", Error))
+	config.Apply(newResultContext("panel-no-targets-rule", "dash1", "panel1", "", Success))
+
+	var unused []string
+	for _, u := range config.Unused() {
+		unused = append(unused, u.String())
+	}
+	require.Equal(t, []string{
+		"exclusions entry {panel 'panel2'} of rule 'panel-units-rule' matched nothing",
+		"exclusions of unknown rule 'unknown-rule'",
+		"warnings of rule 'panel-datasource-rule' matched nothing",
+		"severities of rule 'panel-no-targets-rule' matched nothing",
+	}, unused)
+}
+
+func TestConfigurationExpires(t *testing.T) {
+	config := NewConfigurationFile()
+	require.NoError(t, yaml.Unmarshal([]byte(`
+exclusions:
+  rule1:
+    owner: team-a
+    entries:
+    - panel: panel1
+      expires: 2024-06-30
+      ticket: DASH-1
+      reason: Being fixed
+    - panel: panel2
+      expires: 2024-07-31
+warnings:
+  rule2:
+    expires: 2024-06-30
+severities:
+  rule3:
+    severity: info
+    expires: 2024-07-31
+`), config))
+	require.NoError(t, config.validate())
+	config.Now = func() time.Time { return time.Date(2024, 7, 1, 0, 0, 0, 0, time.Local) }
+
+	for _, tc := range []struct {
+		desc     string
+		rc       ResultContext
+		expected Result
+	}{
+		{
+			desc:     "Should downgrade results of expired exclusions to warnings",
+			rc:       newResultContext("rule1", "dash1", "panel1", "", Error),
+			expected: Result{Severity: Warning, Message: "foo (Exclusion expired on 2024-06-30)"},
+		},
+		{
+			desc:     "Should leave successes of expired exclusions alone",
+			rc:       newResultContext("rule1", "dash1", "panel1", "", Success),
+			expected: Result{Severity: Quiet, Message: "foo"},
+		},
+		{
+			desc:     "Should apply exclusions until they expire",
+			rc:       newResultContext("rule1", "dash1", "panel2", "", Error),
+			expected: Result{Severity: Exclude, Message: "foo (Excluded)"},
+		},
+		{
+			desc:     "Should not apply expired warnings",
+			rc:       newResultContext("rule2", "dash1", "panel1", "", Error),
+			expected: Result{Severity: Error, Message: "foo"},
+		},
+		{
+			desc:     "Should apply severities until they expire",
+			rc:       newResultContext("rule3", "dash1", "panel1", "", Error),
+			expected: Result{Severity: Info, Message: "foo"},
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			require.Equal(t, tc.expected, config.Apply(tc.rc).Result.Results[0].Result)
+		})
+	}
+
+	t.Run("Should record the owner and ticket", func(t *testing.T) {
+		res := config.Apply(newResultContext("rule1", "dash1", "panel1", "", Error))
+		require.Equal(t, "Being fixed", res.Reason)
+		require.Equal(t, "team-a", res.Owner)
+		require.Equal(t, "DASH-1", res.Ticket)
+	})
+
+	t.Run("Should reject invalid dates", func(t *testing.T) {
+		config := NewConfigurationFile()
+		require.NoError(t, yaml.Unmarshal([]byte("exclusions:\n  rule1:\n    entries:\n    - panel: panel1\n      expires: 30/06/2024\n"), config))
+		require.EqualError(t, config.validate(), "invalid expiry date '30/06/2024', must be formatted as YYYY-MM-DD in an entry of rule 'rule1'")
+	})
+}
@@ -0,0 +1,333 @@
+package lint
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// configurationError is an error in the configuration of a rule, or of one of its entries.
+type configurationError struct {
+	section, rule string
+	// entry is the index of the entry the error is about, or -1 if it is about the rule.
+	entry int
+	err   error
+}
+
+func (e *configurationError) Error() string {
+	return e.err.Error()
+}
+
+func (e *configurationError) Unwrap() error {
+	return e.err
+}
+
+func ruleError(section, rule string, err error) error {
+	return &configurationError{section: section, rule: rule, entry: -1, err: err}
+}
+
+func entryError(section, rule string, entry int, err error) error {
+	return &configurationError{section: section, rule: rule, entry: entry, err: err}
+}
+
+func (cf *ConfigurationFile) validate() error {
+	for _, section := range cf.sections() {
+		for _, rule := range sortedKeys(section.rules) {
+			cre := section.rules[rule]
+			if cre == nil {
+				if section.name == "severities" {
+					return ruleError(section.name, rule, fmt.Errorf("no severity set for rule '%s'", rule))
+				}
+				continue
+			}
+			if err := validateExpires(cre.Expires); err != nil {
+				return ruleError(section.name, rule, fmt.Errorf("%w for rule '%s'", err, rule))
+			}
+			for i, ce := range cre.Entries {
+				if err := ce.validate(); err != nil {
+					return entryError(section.name, rule, i, fmt.Errorf("%w in an entry of rule '%s'", err, rule))
+				}
+			}
+		}
+	}
+	if err := cf.validateDeclaredRules(); err != nil {
+		return err
+	}
+	for _, rule := range sortedKeys(cf.Severities) {
+		severities := cf.Severities[rule]
+		if err := validateSeverity(rule, severities.Severity); err != nil {
+			return ruleError("severities", rule, err)
+		}
+		for i, ce := range severities.Entries {
+			if ce.Severity == "" && severities.Severity == "" {
+				return entryError("severities", rule, i, fmt.Errorf("no severity set for an entry of rule '%s'", rule))
+			}
+			if err := validateSeverity(rule, ce.Severity); err != nil {
+				return entryError("severities", rule, i, err)
+			}
+		}
+		if len(severities.Entries) == 0 && severities.Severity == "" {
+			return ruleError("severities", rule, fmt.Errorf("no severity set for rule '%s'", rule))
+		}
+	}
+	return nil
+}
+
+// validateRules returns an error if any rule configured doesn't exist, either built-in, or declared in the
+// configuration or in inherited, the configuration it is merged onto. Rules only configured for alerts are
+// skipped, as they are rules of Mixtool.
+func (cf *ConfigurationFile) validateRules(inherited *ConfigurationFile) error {
+	var names []string
+	known := map[string]bool{}
+	rules, err := inherited.AllRules()
+	if err != nil {
+		return err
+	}
+	declared, err := cf.declaredRules()
+	if err != nil {
+		return err
+	}
+	for _, r := range append(rules.Rules(), declared...) {
+		if !known[r.Name()] {
+			names = append(names, r.Name())
+			known[r.Name()] = true
+		}
+	}
+
+	for _, section := range cf.sections() {
+		for _, rule := range sortedKeys(section.rules) {
+			if known[rule] || isAlertRule(section.rules[rule]) {
+				continue
+			}
+			return ruleError(section.name, rule, fmt.Errorf("unknown rule '%s'%s", rule, suggest(rule, names)))
+		}
+	}
+	return nil
+}
+
+// validateDeclaredRules returns an error if any required matcher or custom rule is invalid, or declares a rule
+// with the name of a built-in rule or of another declared rule. Only required matchers of job and instance can
+// have the names of the built-in rules they replace.
+func (cf *ConfigurationFile) validateDeclaredRules() error {
+	builtin := map[string]bool{}
+	rules := NewRuleSet()
+	for _, r := range rules.Rules() {
+		builtin[r.Name()] = true
+	}
+	replaceable := map[string]bool{}
+	for _, label := range defaultRequiredMatchers {
+		matcherRules, _ := NewRequiredMatcherRules(RequiredMatcher{Label: label})
+		for _, r := range matcherRules {
+			replaceable[r.Name()] = true
+		}
+	}
+	seen := map[string]bool{}
+	for i, m := range cf.RequiredMatchers {
+		// errors about required matchers and custom rules are located by their index
+		index := strconv.Itoa(i)
+		matcherRules, err := NewRequiredMatcherRules(m)
+		if err != nil {
+			return ruleError("required_matchers", index, fmt.Errorf("invalid required matcher '%s': %w", m.Label, err))
+		}
+		for _, r := range matcherRules {
+			if (builtin[r.Name()] && !replaceable[r.Name()]) || seen[r.Name()] {
+				return ruleError("required_matchers", index, fmt.Errorf("invalid required matcher '%s': a rule named '%s' already exists", m.Label, r.Name()))
+			}
+			seen[r.Name()] = true
+		}
+	}
+	for i, cr := range cf.CustomRules {
+		index := strconv.Itoa(i)
+		if _, err := NewCustomRule(cr); err != nil {
+			return ruleError("custom_rules", index, fmt.Errorf("invalid custom rule '%s': %w", cr.Name, err))
+		}
+		if builtin[cr.Name] || seen[cr.Name] {
+			return ruleError("custom_rules", index, fmt.Errorf("invalid custom rule '%s': a rule with the same name already exists", cr.Name))
+		}
+		seen[cr.Name] = true
+	}
+	return nil
+}
+
+// isAlertRule returns true if all entries of the rule are about alerts.
+func isAlertRule(cre *ConfigurationRuleEntries) bool {
+	if cre == nil || len(cre.Entries) == 0 {
+		return false
+	}
+	for _, ce := range cre.Entries {
+		if ce.Alert == "" {
+			return false
+		}
+	}
+	return true
+}
+
+// validate returns an error if any pattern of the entry is invalid.
+func (ce *ConfigurationEntry) validate() error {
+	for _, pattern := range []string{ce.Dashboard, ce.DashboardUID, ce.Panel, ce.Template, ce.Annotation, ce.RefId, ce.File} {
+		if _, err := compilePattern(pattern); err != nil {
+			return fmt.Errorf("invalid pattern '%s': %w", pattern, err)
+		}
+	}
+	if _, err := strconv.Atoi(ce.PanelId); ce.PanelId != "" && err != nil {
+		return fmt.Errorf("invalid panelId '%s', must be a number", ce.PanelId)
+	}
+	if _, err := strconv.Atoi(ce.TargetIdx); ce.TargetIdx != "" && err != nil {
+		return fmt.Errorf("invalid targetIdx '%s', must be a number", ce.TargetIdx)
+	}
+	return validateExpires(ce.Expires)
+}
+
+func validateExpires(expires string) error {
+	if _, err := time.Parse(expiresLayout, expires); expires != "" && err != nil {
+		return fmt.Errorf("invalid expiry date '%s', must be formatted as YYYY-MM-DD", expires)
+	}
+	return nil
+}
+
+func validateSeverity(rule, severity string) error {
+	if _, ok := configSeverities[severity]; severity != "" && !ok {
+		return fmt.Errorf("invalid severity '%s' for rule '%s', must be one of error, warning, info, off", severity, rule)
+	}
+	return nil
+}
+
+// withLine prefixes err with the line of the rule or entry it is about in the YAML document root.
+func withLine(root *yaml.Node, err error) error {
+	var ce *configurationError
+	if !errors.As(err, &ce) {
+		return err
+	}
+	path := []string{ce.section, ce.rule}
+	if ce.entry >= 0 {
+		path = append(path, "entries", strconv.Itoa(ce.entry))
+	}
+	if n := lookupNode(root, path...); n != nil {
+		return fmt.Errorf("line %d: %w", n.Line, err)
+	}
+	return err
+}
+
+// lookupNode returns the node at path in the YAML document root, where the elements of path are either keys of
+// mappings or indexes of sequences. For keys of mappings, the node of the key is returned, so its line is the one
+// of the key.
+func lookupNode(root *yaml.Node, path ...string) *yaml.Node {
+	n := root
+	if n.Kind == yaml.DocumentNode && len(n.Content) > 0 {
+		n = n.Content[0]
+	}
+	var found *yaml.Node
+	for _, elem := range path {
+		found = nil
+		switch n.Kind {
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(n.Content); i += 2 {
+				if n.Content[i].Value == elem {
+					found, n = n.Content[i], n.Content[i+1]
+					break
+				}
+			}
+		case yaml.SequenceNode:
+			if i, err := strconv.Atoi(elem); err == nil && i < len(n.Content) {
+				found, n = n.Content[i], n.Content[i]
+			}
+		}
+		if found == nil {
+			return nil
+		}
+	}
+	return found
+}
+
+// configurationTypes are the types of a configuration file, by their name in errors of the YAML decoder.
+var configurationTypes = map[string]reflect.Type{
+	"lint.ConfigurationFile":        reflect.TypeOf(ConfigurationFile{}),
+	"lint.ConfigurationRuleEntries": reflect.TypeOf(ConfigurationRuleEntries{}),
+	"lint.ConfigurationEntry":       reflect.TypeOf(ConfigurationEntry{}),
+	"lint.RuleSelection":            reflect.TypeOf(RuleSelection{}),
+	"lint.CustomRule":               reflect.TypeOf(CustomRule{}),
+	"lint.RequiredMatcher":          reflect.TypeOf(RequiredMatcher{}),
+}
+
+var unknownFieldRegexp = regexp.MustCompile(`^(line \d+: )field (.+) not found in type (\S+)$`)
+
+// decodeError rewrites the errors of the YAML decoder about unknown keys to suggest the known key meant.
+func decodeError(err error) error {
+	var te *yaml.TypeError
+	if !errors.As(err, &te) {
+		return err
+	}
+	msgs := make([]string, 0, len(te.Errors))
+	for _, msg := range te.Errors {
+		if m := unknownFieldRegexp.FindStringSubmatch(msg); m != nil {
+			msg = fmt.Sprintf("%sunknown key '%s'%s", m[1], m[2], suggest(m[2], yamlKeys(configurationTypes[m[3]])))
+		}
+		msgs = append(msgs, msg)
+	}
+	return errors.New(strings.Join(msgs, "\n"))
+}
+
+// yamlKeys returns the keys of the YAML mapping t is decoded from.
+func yamlKeys(t reflect.Type) []string {
+	if t == nil {
+		return nil
+	}
+	var keys []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		key, _, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+		if !f.IsExported() || key == "-" {
+			continue
+		}
+		if key == "" {
+			key = strings.ToLower(f.Name)
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// suggest returns a suggestion of the candidate s was likely meant to be, if any is close enough.
+func suggest(s string, candidates []string) string {
+	best, bestDistance := "", len(s)/3+1
+	for _, c := range candidates {
+		if strings.EqualFold(s, c) {
+			best = c
+			break
+		}
+		if d := levenshtein(s, c); d <= bestDistance && (best == "" || d < bestDistance) {
+			best, bestDistance = c, d
+		}
+	}
+	if best == "" {
+		return ""
+	}
+	return fmt.Sprintf(", did you mean '%s'?", best)
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
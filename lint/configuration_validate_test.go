@@ -0,0 +1,205 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigurationValidate(t *testing.T) {
+	for _, tc := range []struct {
+		desc   string
+		config string
+		err    string
+	}{
+		{
+			desc: "Should report unknown keys with their line",
+			config: `exclusion:
+  template-job-rule:
+warnings:
+  template-job-rule:
+    entries:
+    - targetIDX: 1
+      dashbord: Node
+`,
+			err: "could not unmarshal lint configuration %s: line 1: unknown key 'exclusion', did you mean 'exclusions'?\n" +
+				"line 6: unknown key 'targetIDX', did you mean 'targetIdx'?\n" +
+				"line 7: unknown key 'dashbord', did you mean 'dashboard'?",
+		},
+		{
+			desc: "Should report unknown rules with their line",
+			config: `exclusions:
+  template-job-rule:
+  panel-unit-rule:
+`,
+			err: "invalid lint configuration %s: line 3: unknown rule 'panel-unit-rule', did you mean 'panel-units-rule'?",
+		},
+		{
+			desc: "Should report invalid entries with their line",
+			config: `severities:
+  panel-units-rule:
+    severity: info
+    entries:
+    - panel: CPU
+    - panel: Memory
+      severity: hint
+`,
+			err: "invalid lint configuration %s: line 6: invalid severity 'hint' for rule 'panel-units-rule', must be one of error, warning, info, off",
+		},
+		{
+			desc: "Should report invalid target indexes",
+			config: `exclusions:
+  target-job-rule:
+    entries:
+    - targetIdx: first
+`,
+			err: "invalid lint configuration %s: line 4: invalid targetIdx 'first', must be a number in an entry of rule 'target-job-rule'",
+		},
+		{
+			desc: "Should report invalid custom rules with their line",
+			config: `custom_rules:
+  - name: team-tag-rule
+    scope: row
+    selector: $
+    message: m
+`,
+			err: "invalid lint configuration %s: line 2: invalid custom rule 'team-tag-rule': invalid scope 'row', must be one of dashboard, template, panel, target",
+		},
+		{
+			desc: "Should report unknown datasources of custom rules with their line",
+			config: `custom_rules:
+  - {name: team-tag-rule, scope: dashboard, selector: $.tags, message: m}
+  - {name: job-rule, scope: target, selector: $.expr, pattern: job, message: m, datasources: [promethues]}
+`,
+			err: "invalid lint configuration %s: line 3: invalid custom rule 'job-rule': invalid datasource 'promethues', must be one of prometheus, loki",
+		},
+		{
+			desc: "Should report custom rules named like other rules",
+			config: `custom_rules:
+  - {name: team-tag-rule, scope: panel, selector: $, message: m}
+  - {name: team-tag-rule, scope: target, selector: $, message: m}
+  - {name: panel-units-rule, scope: panel, selector: $, message: m}
+`,
+			err: "invalid lint configuration %s: line 3: invalid custom rule 'team-tag-rule': a rule with the same name already exists",
+		},
+		{
+			desc: "Should allow configuring custom rules",
+			config: `custom_rules:
+  - {name: team-tag-rule, scope: dashboard, selector: '$.tags[*]', pattern: ^team, message: m}
+exclusions:
+  team-tag-rule:
+`,
+		},
+		{
+			desc: "Should report invalid required matchers with their line",
+			config: `required_matchers:
+  - label: cluster
+  - label: namespace
+    operators: ["=="]
+`,
+			err: "invalid lint configuration %s: line 3: invalid required matcher 'namespace': invalid operator '==', must be one of =, !=, =~, !~",
+		},
+		{
+			desc: "Should report required matchers declaring rules named like other rules",
+			config: `required_matchers:
+  - label: job
+  - label: datasource
+`,
+			err: "invalid lint configuration %s: line 3: invalid required matcher 'datasource': a rule named 'template-datasource-rule' already exists",
+		},
+		{
+			desc: "Should allow configuring the rules of required matchers",
+			config: `required_matchers:
+  - label: cluster
+exclusions:
+  target-cluster-rule:
+  template-cluster-rule:
+`,
+		},
+		{
+			desc: "Should allow rules of Mixtool",
+			config: `exclusions:
+  alert-name-camelcase:
+    entries:
+    - alert: node_down
+`,
+		},
+		{
+			desc:   "Should allow empty files",
+			config: "",
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), ".lint")
+			writeConfig(t, path, tc.config)
+			err := NewConfigurationFile().Load(path)
+			if tc.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, fmt.Sprintf(tc.err, path))
+		})
+	}
+
+	t.Run("Should decode target indexes", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), ".lint")
+		writeConfig(t, path, "exclusions:\n  target-job-rule:\n    entries:\n    - targetIdx: 2\n")
+		config := NewConfigurationFile()
+		require.NoError(t, config.Load(path))
+		require.Equal(t, "2", config.Exclusions["target-job-rule"].Entries[0].TargetIdx)
+	})
+
+	t.Run("Should decode the legacy targetidx key", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), ".lint")
+		writeConfig(t, path, "exclusions:\n  target-job-rule:\n    entries:\n    - targetidx: 1\n      panel: CPU\n")
+		config := NewConfigurationFile()
+		require.NoError(t, config.Load(path))
+		entry := config.Exclusions["target-job-rule"].Entries[0]
+		require.Equal(t, "1", entry.TargetIdx)
+		require.Equal(t, "CPU", entry.Panel)
+	})
+}
+
+// TestConfigurationSchema checks that the JSON schema published for configuration files is up to date.
+func TestConfigurationSchema(t *testing.T) {
+	buf, err := os.ReadFile("../docs/lint.schema.json")
+	require.NoError(t, err)
+
+	type object struct {
+		Properties map[string]json.RawMessage `json:"properties"`
+	}
+	var schema struct {
+		object
+		Definitions map[string]object `json:"definitions"`
+	}
+	require.NoError(t, json.Unmarshal(buf, &schema))
+
+	keys := func(o object) []string {
+		return sortedKeys(o.Properties)
+	}
+	sorted := func(s []string) []string {
+		s = append([]string{}, s...)
+		sort.Strings(s)
+		return s
+	}
+
+	require.Equal(t, sorted(yamlKeys(reflect.TypeOf(ConfigurationFile{}))), keys(schema.object))
+	require.Equal(t, sorted(yamlKeys(reflect.TypeOf(RuleSelection{}))), keys(schema.Definitions["ruleSelection"]))
+	require.Equal(t, sorted(yamlKeys(reflect.TypeOf(ConfigurationRuleEntries{}))), keys(schema.Definitions["rule"]))
+	require.Equal(t, sorted(yamlKeys(reflect.TypeOf(ConfigurationEntry{}))), keys(schema.Definitions["entry"]))
+	require.Equal(t, sorted(yamlKeys(reflect.TypeOf(RequiredMatcher{}))), keys(schema.Definitions["requiredMatcher"]))
+	require.Equal(t, sorted(yamlKeys(reflect.TypeOf(CustomRule{}))), keys(schema.Definitions["customRule"]))
+
+	var names []string
+	rules := NewRuleSet()
+	for _, r := range rules.Rules() {
+		names = append(names, r.Name())
+	}
+	require.Equal(t, sorted(names), keys(schema.Definitions["section"]))
+}
@@ -0,0 +1,187 @@
+package lint
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around every change in a unified diff.
+const diffContext = 3
+
+// diffLine is a line of a diff: kind is ' ' for lines in both files, '-' for lines only in the old file and '+'
+// for lines only in the new one. a and b are the 0-based indices of the line in the old and new file, or of the
+// line it comes before if it isn't in that file.
+type diffLine struct {
+	kind byte
+	text string
+	a, b int
+}
+
+// UnifiedDiff returns the unified diff turning old into new, labelling the files from and to, or an empty
+// string if they are the same.
+func UnifiedDiff(old, new []byte, from, to string) string {
+	lines := diffLines(splitLines(string(old)), splitLines(string(new)))
+
+	var b strings.Builder
+	for i := 0; i < len(lines); {
+		if lines[i].kind == ' ' {
+			i++
+			continue
+		}
+		// Extend the hunk until the next change is too far away to share context with the previous one
+		start := max(0, i-diffContext)
+		end := i
+		for j := i; j < len(lines) && j-end <= 2*diffContext; j++ {
+			if lines[j].kind != ' ' {
+				end = j
+			}
+		}
+		stop := min(len(lines), end+diffContext+1)
+
+		if b.Len() == 0 {
+			fmt.Fprintf(&b, "--- %s\n+++ %s\n", from, to)
+		}
+		writeHunk(&b, lines[start:stop])
+		i = stop
+	}
+	return b.String()
+}
+
+func writeHunk(b *strings.Builder, lines []diffLine) {
+	aCount, bCount := 0, 0
+	for _, l := range lines {
+		if l.kind != '+' {
+			aCount++
+		}
+		if l.kind != '-' {
+			bCount++
+		}
+	}
+	fmt.Fprintf(b, "@@ -%s +%s @@\n", hunkRange(lines[0].a, aCount), hunkRange(lines[0].b, bCount))
+	for _, l := range lines {
+		b.WriteByte(l.kind)
+		b.WriteString(l.text)
+		if !strings.HasSuffix(l.text, "\n") {
+			b.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
+
+// hunkRange formats the range of a hunk the way diff does: empty ranges start at the line before them, and
+// the length is left out of ranges of a single line.
+func hunkRange(start, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", start)
+	case 1:
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+// splitLines splits s into lines, keeping their line endings so a missing newline at the end is a change too.
+func splitLines(s string) []string {
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffLines returns the shortest diff turning a into b, using Myers' algorithm. Fixes only touch a few lines,
+// so the common prefix and suffix are stripped first to keep the edit graph small.
+func diffLines(a, b []string) []diffLine {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var lines []diffLine
+	for i := 0; i < prefix; i++ {
+		lines = append(lines, diffLine{' ', a[i], i, i})
+	}
+	for _, l := range myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]) {
+		l.a += prefix
+		l.b += prefix
+		lines = append(lines, l)
+	}
+	for i := suffix; i > 0; i-- {
+		lines = append(lines, diffLine{' ', a[len(a)-i], len(a) - i, len(b) - i})
+	}
+	return lines
+}
+
+func myers(a, b []string) []diffLine {
+	n, m := len(a), len(b)
+	offset := n + m + 1
+	v := make([]int, 2*offset+1)
+	// trace holds, for every d, the furthest reaching x of every diagonal k in [-d, d] before step d
+	var trace [][]int
+
+	for d := 0; ; d++ {
+		trace = append(trace, append([]int(nil), v[offset-d:offset+d+1]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || k != d && v[offset+k-1] < v[offset+k+1] {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrack(a, b, trace, d)
+			}
+		}
+	}
+}
+
+// backtrack walks the edit graph back from its end to its start, collecting the lines along the way.
+func backtrack(a, b []string, trace [][]int, d int) []diffLine {
+	x, y := len(a), len(b)
+	var reversed []diffLine
+	for ; d > 0; d-- {
+		v := trace[d]
+		at := func(k int) int { return v[k+d] }
+
+		k := x - y
+		prevK := k - 1
+		if k == -d || k != d && at(k-1) < at(k+1) {
+			prevK = k + 1
+		}
+		prevX := at(prevK)
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			x--
+			y--
+			reversed = append(reversed, diffLine{' ', a[x], x, y})
+		}
+		if x == prevX {
+			y--
+			reversed = append(reversed, diffLine{'+', b[y], x, y})
+		} else {
+			x--
+			reversed = append(reversed, diffLine{'-', a[x], x, y})
+		}
+	}
+	for x > 0 && y > 0 {
+		x--
+		y--
+		reversed = append(reversed, diffLine{' ', a[x], x, y})
+	}
+
+	lines := make([]diffLine, len(reversed))
+	for i, l := range reversed {
+		lines[len(lines)-1-i] = l
+	}
+	return lines
+}
@@ -0,0 +1,134 @@
+package lint
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	numbered := func(from, to int) string {
+		var b strings.Builder
+		for i := from; i <= to; i++ {
+			fmt.Fprintf(&b, "%d\n", i)
+		}
+		return b.String()
+	}
+
+	for _, tc := range []struct {
+		desc     string
+		old, new string
+		expected string
+	}{
+		{
+			desc:     "Should return nothing for equal files",
+			old:      "a\nb\n",
+			new:      "a\nb\n",
+			expected: "",
+		},
+		{
+			desc: "Should show context around a change",
+			old:  numbered(1, 10),
+			new:  strings.Replace(numbered(1, 10), "5\n", "five\n", 1),
+			expected: `--- old
++++ new
+@@ -2,7 +2,7 @@
+ 2
+ 3
+ 4
+-5
++five
+ 6
+ 7
+ 8
+`,
+		},
+		{
+			desc: "Should split far apart changes into hunks",
+			old:  numbered(1, 20),
+			new:  strings.Replace(strings.Replace(numbered(1, 20), "2\n", "", 1), "19\n", "19\nnew\n", 1),
+			expected: `--- old
++++ new
+@@ -1,5 +1,4 @@
+ 1
+-2
+ 3
+ 4
+ 5
+@@ -17,4 +16,5 @@
+ 17
+ 18
+ 19
++new
+ 20
+`,
+		},
+		{
+			desc: "Should merge close changes into one hunk",
+			old:  numbered(1, 9),
+			new:  strings.Replace(strings.Replace(numbered(1, 9), "2\n", "two\n", 1), "8\n", "eight\n", 1),
+			expected: `--- old
++++ new
+@@ -1,9 +1,9 @@
+ 1
+-2
++two
+ 3
+ 4
+ 5
+ 6
+ 7
+-8
++eight
+ 9
+`,
+		},
+		{
+			desc: "Should diff files without a newline at the end",
+			old:  "a\nb",
+			new:  "a\nb\n",
+			expected: `--- old
++++ new
+@@ -1,2 +1,2 @@
+ a
+-b
+\ No newline at end of file
++b
+`,
+		},
+		{
+			desc: "Should diff empty files",
+			old:  "",
+			new:  "a\n",
+			expected: `--- old
++++ new
+@@ -0,0 +1 @@
++a
+`,
+		},
+		{
+			desc: "Should find the shortest diff",
+			old:  "a\nb\nc\na\nb\nb\na\n",
+			new:  "c\nb\na\nb\na\nc\n",
+			expected: `--- old
++++ new
+@@ -1,7 +1,6 @@
+-a
+-b
+ c
++b
+ a
+ b
+-b
+ a
++c
+`,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			require.Equal(t, tc.expected, UnifiedDiff([]byte(tc.old), []byte(tc.new), "old", "new"))
+		})
+	}
+}
@@ -0,0 +1,616 @@
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// edit replaces the bytes [start, end) of a source with text.
+type edit struct {
+	start, end int
+	text       string
+}
+
+// member is an object member to be added to an object of a source.
+type member struct {
+	key   string
+	value interface{}
+}
+
+// objectEdit collects the members removed from, and added to, an object of a source. These are turned into
+// edits together, as removing neighbouring members affects the same separators.
+type objectEdit struct {
+	removed map[string]bool
+	added   []member
+}
+
+// arrayEdit collects the elements removed from, and inserted into, an array of a source. Inserted elements are
+// kept by the index of the element of the source they follow, or -1 if they come before all kept elements.
+type arrayEdit struct {
+	removed  map[int]bool
+	inserted map[int][]interface{}
+}
+
+// editor finds what changed between the dashboard as parsed and as it is now, and turns those changes into
+// edits of its source. Only the values which were changed are touched, keeping the key order, formatting and
+// fields unknown to the dashboard model everywhere else.
+type editor struct {
+	src     *source
+	next    interface{}
+	edits   []edit
+	objects map[string]*objectEdit
+	arrays  map[string]*arrayEdit
+	// written are the pointers of values which are written as a whole, so nothing within them is edited.
+	written map[string]bool
+	// moved are the pointers in next of the elements of arrays which are at another index than in the source.
+	moved map[string]string
+}
+
+// marshal returns the source with all changes made to the dashboard since it was parsed applied to it.
+func (s *source) marshal(d *Dashboard) ([]byte, error) {
+	current, err := json.Marshal(d)
+	if err != nil {
+		return nil, err
+	}
+	base, err := decodeTree(s.model)
+	if err != nil {
+		return nil, err
+	}
+	next, err := decodeTree(current)
+	if err != nil {
+		return nil, err
+	}
+
+	e := editor{
+		src:     s,
+		next:    next,
+		objects: make(map[string]*objectEdit),
+		arrays:  make(map[string]*arrayEdit),
+		written: make(map[string]bool),
+		moved:   make(map[string]string),
+	}
+	e.diff("", "", base, next)
+	return e.apply(), nil
+}
+
+func decodeTree(buf []byte) (interface{}, error) {
+	var tree interface{}
+	dec := json.NewDecoder(bytes.NewReader(buf))
+	dec.UseNumber()
+	err := dec.Decode(&tree)
+	return tree, err
+}
+
+// diff finds what changed between a, the value at pointer in the source, and b, the value at nextPointer in next.
+// The pointers only differ within elements of arrays which moved.
+func (e *editor) diff(pointer, nextPointer string, a, b interface{}) {
+	switch av := a.(type) {
+	case map[string]interface{}:
+		bv, ok := b.(map[string]interface{})
+		if !ok {
+			e.replace(pointer, b)
+			return
+		}
+		for _, k := range sortedKeys(av) {
+			if bk, ok := bv[k]; ok {
+				e.diff(pointerJoin(pointer, k), pointerJoin(nextPointer, k), av[k], bk)
+			} else {
+				e.remove(pointerJoin(pointer, k), av[k])
+			}
+		}
+		for _, k := range sortedKeys(bv) {
+			if _, ok := av[k]; !ok {
+				e.replace(pointerJoin(pointer, k), bv[k])
+			}
+		}
+	case []interface{}:
+		bv, ok := b.([]interface{})
+		if !ok {
+			e.replace(pointer, b)
+			return
+		}
+		e.diffArray(pointer, nextPointer, av, bv)
+	default:
+		if !reflect.DeepEqual(a, b) {
+			e.replace(pointer, b)
+		}
+	}
+}
+
+// diffArray finds what changed between the arrays a and b. Elements which are the same in both, changed or not,
+// are edited in place, and only the others are removed or inserted, so the elements which are kept keep the
+// fields unknown to the dashboard model. If kept elements were reordered, the array is written as a whole, with
+// the unknown fields of the kept elements added back.
+func (e *editor) diffArray(pointer, nextPointer string, a, b []interface{}) {
+	matches := matchElements(a, b)
+	kept := make(map[int]bool)
+	ordered := true
+	last := -1
+	for _, i := range matches {
+		if i < 0 {
+			continue
+		}
+		kept[i] = true
+		if i < last {
+			ordered = false
+		}
+		last = i
+	}
+
+	if len(kept) == 0 {
+		if len(a) != 0 || len(b) != 0 {
+			e.replace(pointer, b)
+		}
+		return
+	}
+	if !ordered {
+		merged := make([]interface{}, len(b))
+		for j, i := range matches {
+			merged[j] = b[j]
+			if i >= 0 {
+				if sp, ok := e.src.spans[pointerJoin(pointer, i)]; ok {
+					if src, err := decodeTree(e.src.buf[sp.Start:sp.End]); err == nil {
+						merged[j] = withUnknown(src, a[i], b[j])
+					}
+				}
+			}
+		}
+		e.replace(pointer, merged)
+		return
+	}
+
+	arr := &arrayEdit{removed: make(map[int]bool), inserted: make(map[int][]interface{})}
+	anchor := -1
+	for j, i := range matches {
+		if i < 0 {
+			arr.inserted[anchor] = append(arr.inserted[anchor], b[j])
+			continue
+		}
+		anchor = i
+		element, nextElement := pointerJoin(pointer, i), pointerJoin(nextPointer, j)
+		if element != nextElement {
+			e.moved[element] = nextElement
+		}
+		e.diff(element, nextElement, a[i], b[j])
+	}
+	for i := range a {
+		if !kept[i] {
+			arr.removed[i] = true
+		}
+	}
+	if len(arr.removed) > 0 || len(arr.inserted) > 0 {
+		e.arrays[pointer] = arr
+	}
+}
+
+// elementKey returns what identifies an element of an array across changes: the refId of targets, or the id of
+// panels, or an empty string if it has neither.
+func elementKey(v interface{}) string {
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	if refID, ok := obj["refId"].(string); ok && refID != "" {
+		return "refId\x00" + refID
+	}
+	if id, ok := obj["id"].(json.Number); ok && id != "0" {
+		return "id\x00" + id.String()
+	}
+	return ""
+}
+
+// matchElements returns, for each element of b, the index of the same element in a, or -1 if it was inserted.
+// Elements with a refId or id are matched by it, and other elements by their position.
+func matchElements(a, b []interface{}) []int {
+	aKeys, bKeys := uniqueKeys(a), uniqueKeys(b)
+	byKey := make(map[string]int)
+	for i, k := range aKeys {
+		if k != "" {
+			byKey[k] = i
+		}
+	}
+
+	matches := make([]int, len(b))
+	used := make(map[int]bool)
+	for j, k := range bKeys {
+		matches[j] = -1
+		if i, ok := byKey[k]; ok && k != "" {
+			matches[j] = i
+			used[i] = true
+		}
+	}
+	for j, k := range bKeys {
+		if k == "" && j < len(a) && aKeys[j] == "" && !used[j] {
+			matches[j] = j
+			used[j] = true
+		}
+	}
+	return matches
+}
+
+// uniqueKeys returns the keys of the elements of arr, leaving out keys shared by several elements.
+func uniqueKeys(arr []interface{}) []string {
+	keys := make([]string, len(arr))
+	count := make(map[string]int)
+	for i, v := range arr {
+		keys[i] = elementKey(v)
+		count[keys[i]]++
+	}
+	for i, k := range keys {
+		if count[k] > 1 {
+			keys[i] = ""
+		}
+	}
+	return keys
+}
+
+// withUnknown returns next with the members of src which are unknown to the model, as they are missing from model,
+// added back to it, recursively.
+func withUnknown(src, model, next interface{}) interface{} {
+	switch nv := next.(type) {
+	case map[string]interface{}:
+		sv, ok := src.(map[string]interface{})
+		mv, _ := model.(map[string]interface{})
+		if !ok {
+			return next
+		}
+		merged := make(map[string]interface{}, len(nv))
+		for k, v := range nv {
+			merged[k] = v
+			if s, ok := sv[k]; ok {
+				merged[k] = withUnknown(s, mv[k], v)
+			}
+		}
+		for k, v := range sv {
+			if _, known := mv[k]; !known {
+				if _, set := nv[k]; !set {
+					merged[k] = v
+				}
+			}
+		}
+		return merged
+	case []interface{}:
+		sv, ok := src.([]interface{})
+		mv, _ := model.([]interface{})
+		if !ok || len(sv) != len(nv) || len(mv) != len(nv) {
+			return next
+		}
+		merged := make([]interface{}, len(nv))
+		for i := range nv {
+			merged[i] = withUnknown(sv[i], mv[i], nv[i])
+		}
+		return merged
+	}
+	return next
+}
+
+// replace sets the value at pointer, adding it to its parent object if it isn't in the source.
+func (e *editor) replace(pointer string, v interface{}) {
+	if e.written[pointer] || e.writtenAncestor(pointer) {
+		return
+	}
+	if sp, ok := e.src.spans[pointer]; ok {
+		e.edits = append(e.edits, edit{sp.Start, sp.End, e.marshalValue(v, e.indentAt(sp.Start), bytes.ContainsRune(e.src.buf[sp.Start:sp.End], '\n'))})
+		e.written[pointer] = true
+		return
+	}
+
+	parent, key := pointerSplit(pointer)
+	if sp, ok := e.src.spans[parent]; !ok || e.src.buf[sp.Start] != '{' {
+		// The parent is missing too, or isn't an object, so it has to be replaced as a whole
+		e.replace(parent, e.nextValue(parent))
+		return
+	}
+	e.object(parent).added = append(e.object(parent).added, member{key, v})
+	e.written[pointer] = true
+}
+
+// remove removes the value at pointer, which was removed from the dashboard model. Scalars are most likely
+// dropped because they were set to their zero value on an 'omitempty' field, so they are set to that instead.
+func (e *editor) remove(pointer string, old interface{}) {
+	if _, ok := e.src.keys[pointer]; !ok || e.writtenAncestor(pointer) {
+		return
+	}
+	switch old.(type) {
+	case bool:
+		e.replace(pointer, false)
+	case json.Number:
+		e.replace(pointer, json.Number("0"))
+	case string:
+		e.replace(pointer, "")
+	default:
+		parent, _ := pointerSplit(pointer)
+		obj := e.object(parent)
+		if obj.removed == nil {
+			obj.removed = make(map[string]bool)
+		}
+		obj.removed[pointer] = true
+	}
+}
+
+// nextValue returns the value in next of the value at pointer in the source.
+func (e *editor) nextValue(pointer string) interface{} {
+	for p := pointer; p != ""; p, _ = pointerSplit(p) {
+		if moved, ok := e.moved[p]; ok {
+			return pointerLookup(e.next, moved+pointer[len(p):])
+		}
+	}
+	return pointerLookup(e.next, pointer)
+}
+
+func (e *editor) writtenAncestor(pointer string) bool {
+	for pointer != "" {
+		pointer, _ = pointerSplit(pointer)
+		if e.written[pointer] {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *editor) object(pointer string) *objectEdit {
+	obj, ok := e.objects[pointer]
+	if !ok {
+		obj = &objectEdit{}
+		e.objects[pointer] = obj
+	}
+	return obj
+}
+
+// objectEdits turns the members removed from, and added to, the object at pointer into edits.
+func (e *editor) objectEdits(pointer string, obj *objectEdit) []edit {
+	sp := e.src.spans[pointer]
+	members := e.src.members[pointer]
+	var edits []edit
+
+	kept := 0
+	for i := 0; i < len(members); {
+		if !obj.removed[members[i]] {
+			kept++
+			i++
+			continue
+		}
+		// Remove the whole run of consecutive removed members, along with their separators
+		j := i
+		for j+1 < len(members) && obj.removed[members[j+1]] {
+			j++
+		}
+		switch {
+		case i > 0:
+			edits = append(edits, edit{e.src.spans[members[i-1]].End, e.src.spans[members[j]].End, ""})
+		case j+1 < len(members):
+			edits = append(edits, edit{e.src.keys[members[0]], e.src.keys[members[j+1]], ""})
+		}
+		i = j + 1
+	}
+
+	if len(members) > 0 && kept == 0 {
+		// Everything was removed, so the inside of the object is rewritten as a whole
+		return []edit{{sp.Start + 1, sp.End - 1, e.membersText(pointer, obj.added, "", false)}}
+	}
+	if len(obj.added) > 0 {
+		if len(members) == 0 {
+			edits = append(edits, edit{sp.Start + 1, sp.End - 1, e.membersText(pointer, obj.added, "", false)})
+		} else {
+			last := members[len(members)-1]
+			indent, pretty := e.memberIndent(pointer)
+			edits = append(edits, edit{e.src.spans[last].End, e.src.spans[last].End, e.membersText(pointer, obj.added, indent, pretty)})
+		}
+	}
+	return edits
+}
+
+// arrayEdits turns the elements removed from, and inserted into, the array at pointer into edits.
+func (e *editor) arrayEdits(pointer string, arr *arrayEdit) []edit {
+	var elements []span
+	for i := 0; ; i++ {
+		sp, ok := e.src.spans[pointerJoin(pointer, i)]
+		if !ok {
+			break
+		}
+		elements = append(elements, sp)
+	}
+	var edits []edit
+
+	first := -1
+	for i := range elements {
+		if !arr.removed[i] {
+			first = i
+			break
+		}
+	}
+	for i := 0; i < len(elements); {
+		if !arr.removed[i] {
+			i++
+			continue
+		}
+		// Remove the whole run of consecutive removed elements, along with their separators
+		j := i
+		for j+1 < len(elements) && arr.removed[j+1] {
+			j++
+		}
+		if i > 0 {
+			edits = append(edits, edit{elements[i-1].End, elements[j].End, ""})
+		} else {
+			edits = append(edits, edit{elements[0].Start, elements[j+1].Start, ""})
+		}
+		i = j + 1
+	}
+
+	indent, pretty := e.elementIndent(elements[first].Start)
+	separator := ", "
+	if pretty {
+		separator = ",\n" + indent
+	}
+	for _, anchor := range sortedInts(arr.inserted) {
+		var b strings.Builder
+		for _, v := range arr.inserted[anchor] {
+			if anchor >= 0 {
+				b.WriteString(separator)
+			}
+			b.WriteString(e.marshalValue(v, indent, pretty))
+			if anchor < 0 {
+				b.WriteString(separator)
+			}
+		}
+		if anchor < 0 {
+			edits = append(edits, edit{elements[first].Start, elements[first].Start, b.String()})
+		} else {
+			edits = append(edits, edit{elements[anchor].End, elements[anchor].End, b.String()})
+		}
+	}
+	return edits
+}
+
+// elementIndent returns the indentation of the element of an array starting at offset, and whether it is on a
+// line of its own.
+func (e *editor) elementIndent(offset int) (string, bool) {
+	lineStart := bytes.LastIndexByte(e.src.buf[:offset], '\n') + 1
+	indent := e.src.buf[lineStart:offset]
+	if lineStart == 0 || len(bytes.TrimLeft(indent, " \t")) > 0 {
+		return "", false
+	}
+	return string(indent), true
+}
+
+// membersText returns the text of members to add to an object, each preceded by a separator if the object
+// already has members.
+func (e *editor) membersText(pointer string, members []member, indent string, pretty bool) string {
+	var b strings.Builder
+	keep := len(e.src.members[pointer]) > len(e.objects[pointer].removed)
+	for i, m := range members {
+		key, _ := json.Marshal(m.key)
+		switch {
+		case pretty:
+			b.WriteString(",\n" + indent)
+		case keep || i > 0:
+			b.WriteString(", ")
+		}
+		b.Write(key)
+		b.WriteString(": ")
+		b.WriteString(e.marshalValue(m.value, indent, pretty))
+	}
+	return b.String()
+}
+
+// memberIndent returns the indentation of the members of the object at pointer, and whether the members are on
+// lines of their own.
+func (e *editor) memberIndent(pointer string) (string, bool) {
+	members := e.src.members[pointer]
+	keyStart := e.src.keys[members[len(members)-1]]
+	lineStart := bytes.LastIndexByte(e.src.buf[:keyStart], '\n') + 1
+	if lineStart <= e.src.spans[pointer].Start {
+		return "", false
+	}
+	indent := e.src.buf[lineStart:keyStart]
+	if len(bytes.TrimLeft(indent, " \t")) > 0 {
+		return "", false
+	}
+	return string(indent), true
+}
+
+// indentAt returns the indentation of the line containing offset.
+func (e *editor) indentAt(offset int) string {
+	lineStart := bytes.LastIndexByte(e.src.buf[:offset], '\n') + 1
+	line := e.src.buf[lineStart:offset]
+	return string(line[:len(line)-len(bytes.TrimLeft(line, " \t"))])
+}
+
+// marshalValue marshals v to be written into the source. Objects and arrays are indented, starting at indent,
+// if they are to be written over multiple lines.
+func (e *editor) marshalValue(v interface{}, indent string, multiline bool) string {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	switch v.(type) {
+	case map[string]interface{}, []interface{}:
+		if multiline {
+			unit := "  "
+			if strings.Contains(indent, "\t") {
+				unit = "\t"
+			}
+			enc.SetIndent(indent, unit)
+		}
+	}
+	// Encoding generic values decoded from JSON can't fail
+	_ = enc.Encode(v)
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// apply returns the source with all edits applied.
+func (e *editor) apply() []byte {
+	edits := e.edits
+	for _, pointer := range sortedKeys(e.objects) {
+		edits = append(edits, e.objectEdits(pointer, e.objects[pointer])...)
+	}
+	for _, pointer := range sortedKeys(e.arrays) {
+		if e.written[pointer] || e.writtenAncestor(pointer) {
+			continue
+		}
+		edits = append(edits, e.arrayEdits(pointer, e.arrays[pointer])...)
+	}
+	sort.SliceStable(edits, func(i, j int) bool {
+		if edits[i].start != edits[j].start {
+			return edits[i].start > edits[j].start
+		}
+		return edits[i].end > edits[j].end
+	})
+
+	buf := append([]byte(nil), e.src.buf...)
+	for _, ed := range edits {
+		buf = append(buf[:ed.start], append([]byte(ed.text), buf[ed.end:]...)...)
+	}
+	return buf
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedInts[V any](m map[int]V) []int {
+	keys := make([]int, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	return keys
+}
+
+var pointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+
+// pointerSplit splits a JSON pointer into the pointer of its parent and its last, unescaped, reference token.
+func pointerSplit(pointer string) (string, string) {
+	i := strings.LastIndexByte(pointer, '/')
+	if i < 0 {
+		return "", ""
+	}
+	return pointer[:i], pointerUnescaper.Replace(pointer[i+1:])
+}
+
+// pointerLookup returns the value at pointer within tree, or nil if there is none.
+func pointerLookup(tree interface{}, pointer string) interface{} {
+	if pointer == "" {
+		return tree
+	}
+	parent, token := pointerSplit(pointer)
+	switch v := pointerLookup(tree, parent).(type) {
+	case map[string]interface{}:
+		return v[token]
+	case []interface{}:
+		i, err := strconv.Atoi(token)
+		if err != nil || i < 0 || i >= len(v) {
+			return nil
+		}
+		return v[i]
+	}
+	return nil
+}
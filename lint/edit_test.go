@@ -0,0 +1,202 @@
+package lint
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLosslessMarshal(t *testing.T) {
+	for _, tc := range []struct {
+		desc     string
+		input    string
+		fix      func(d *Dashboard)
+		expected string
+	}{
+		{
+			desc: "Should keep unchanged dashboards as is",
+			input: `{
+    "unknown": {"b": 1.50, "a": [1e3, "<&>"]},
+    "title": "test",
+    "panels": [ {"id": 1, "type": "graph"} ]
+}`,
+			fix: func(d *Dashboard) {},
+			expected: `{
+    "unknown": {"b": 1.50, "a": [1e3, "<&>"]},
+    "title": "test",
+    "panels": [ {"id": 1, "type": "graph"} ]
+}`,
+		},
+		{
+			desc: "Should only replace changed values",
+			input: `{
+  "editable": true,
+  "title": "test",
+  "weird":   [1.0],
+  "panels": [{"type": "row", "panels": [{"targets": [{"expr": "up", "refId": "A", "extra": 1}]}]}]
+}`,
+			fix: func(d *Dashboard) {
+				d.Editable = false
+				d.Title = "<fixed>"
+				d.Panels[0].Panels[0].Targets[0].Expr = "up{job=~\"$job\"}"
+			},
+			expected: `{
+  "editable": false,
+  "title": "<fixed>",
+  "weird":   [1.0],
+  "panels": [{"type": "row", "panels": [{"targets": [{"expr": "up{job=~\"$job\"}", "refId": "A", "extra": 1}]}]}]
+}`,
+		},
+		{
+			desc: "Should add missing values with matching indentation",
+			input: `{
+  "templating": {
+    "list": [
+      {
+        "name": "job",
+        "type": "query",
+        "query": "label_values(job)"
+      }
+    ]
+  }
+}`,
+			fix: func(d *Dashboard) {
+				d.Templating.List[0].Refresh = 2
+				d.Templating.List[0].Options = []RawTemplateValue{{"text": "a"}}
+			},
+			expected: `{
+  "templating": {
+    "list": [
+      {
+        "name": "job",
+        "type": "query",
+        "query": "label_values(job)",
+        "options": [
+          {
+            "text": "a"
+          }
+        ],
+        "refresh": 2
+      }
+    ]
+  }
+}`,
+		},
+		{
+			desc:  "Should add missing parents",
+			input: `{"title": "test"}`,
+			fix: func(d *Dashboard) {
+				d.Panels = []Panel{{Id: 1, Type: "graph"}}
+			},
+			expected: `{"title": "test", "panels": [{"id":1,"title":"","type":"graph"}]}`,
+		},
+		{
+			desc: "Should remove values dropped from the model",
+			input: `{
+  "panels": [
+    {
+      "fieldConfig": {"defaults": {"unit": "s"}},
+      "id": 1,
+      "description": "foo",
+      "type": "graph"
+    },
+    {"id": 2, "fieldConfig": {"defaults": {"unit": "s"}}}
+  ]
+}`,
+			fix: func(d *Dashboard) {
+				d.Panels[0].FieldConfig = nil
+				d.Panels[0].Description = ""
+				d.Panels[1].FieldConfig = nil
+			},
+			expected: `{
+  "panels": [
+    {
+      "id": 1,
+      "description": "",
+      "type": "graph"
+    },
+    {"id": 2}
+  ]
+}`,
+		},
+		{
+			desc:  "Should only insert the elements appended to arrays",
+			input: `{"panels": [{"id": 1, "targets": [{"expr": "a", "extra": true}]}]}`,
+			fix: func(d *Dashboard) {
+				d.Panels[0].Targets = append(d.Panels[0].Targets, Target{Expr: "b"})
+			},
+			expected: `{"panels": [{"id": 1, "targets": [{"expr": "a", "extra": true}, {"expr":"b","hide":false}]}]}`,
+		},
+		{
+			desc: "Should only remove the elements removed from arrays, matched by refId",
+			input: `{"panels": [{"id": 1, "targets": [
+  {"refId": "A", "expr": "a", "extra": 1},
+  {"refId": "B", "expr": "b", "extra": 2},
+  {"refId": "C", "expr": "c", "extra": 3}
+]}]}`,
+			fix: func(d *Dashboard) {
+				targets := d.Panels[0].Targets
+				d.Panels[0].Targets = []Target{targets[0], targets[2]}
+				d.Panels[0].Targets[1].Expr = "sum(c)"
+			},
+			expected: `{"panels": [{"id": 1, "targets": [
+  {"refId": "A", "expr": "a", "extra": 1},
+  {"refId": "C", "expr": "sum(c)", "extra": 3}
+]}]}`,
+		},
+		{
+			desc: "Should insert and remove elements of arrays matched by id, keeping their formatting",
+			input: `{
+  "panels": [
+    {"id": 1, "type": "graph", "extra": 1},
+    {"id": 2, "type": "graph", "extra": 2}
+  ]
+}`,
+			fix: func(d *Dashboard) {
+				d.Panels = []Panel{{Id: 3, Type: "text"}, d.Panels[1]}
+			},
+			expected: `{
+  "panels": [
+    {
+      "id": 3,
+      "title": "",
+      "type": "text"
+    },
+    {"id": 2, "type": "graph", "extra": 2}
+  ]
+}`,
+		},
+		{
+			desc:  "Should keep unknown fields of elements of arrays which were reordered",
+			input: `{"panels": [{"id": 1, "type": "graph", "extra": 1}, {"id": 2, "type": "graph", "extra": 2}]}`,
+			fix: func(d *Dashboard) {
+				d.Panels[0], d.Panels[1] = d.Panels[1], d.Panels[0]
+			},
+			expected: `{"panels": [{"extra":2,"id":2,"title":"","type":"graph"},{"extra":1,"id":1,"title":"","type":"graph"}]}`,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			d, err := NewDashboard([]byte(tc.input))
+			require.NoError(t, err)
+
+			tc.fix(&d)
+
+			actual, err := d.Marshal()
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, string(actual))
+		})
+	}
+
+	t.Run("Should keep the sample dashboard as is", func(t *testing.T) {
+		sampleDashboard, err := os.ReadFile("testdata/dashboard.json")
+		require.NoError(t, err)
+
+		d, err := NewDashboard(sampleDashboard)
+		require.NoError(t, err)
+
+		actual, err := d.Marshal()
+		require.NoError(t, err)
+		require.Equal(t, string(sampleDashboard), string(actual))
+	})
+}
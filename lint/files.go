@@ -0,0 +1,89 @@
+package lint
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// FindDashboards expands the given paths into the list of dashboard files to lint. Paths which are files
+// are always returned as-is. Directories are walked recursively, returning every file whose base name
+// matches at least one of the include globs. Files and directories matching any of the exclude globs,
+// either by base name or by their slash separated path relative to the walked directory, are skipped.
+// Each file is returned only once, in the order it was first found.
+func FindDashboards(paths []string, include, exclude []string) ([]string, error) {
+	var files []string
+	seen := make(map[string]struct{})
+	add := func(file string) {
+		if _, ok := seen[file]; ok {
+			return
+		}
+		seen[file] = struct{}{}
+		files = append(files, file)
+	}
+
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			add(p)
+			continue
+		}
+
+		err = filepath.WalkDir(p, func(file string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if file == p {
+				return nil
+			}
+			rel, err := filepath.Rel(p, file)
+			if err != nil {
+				return err
+			}
+			excluded, err := matchesAnyGlob(exclude, entry.Name(), filepath.ToSlash(rel))
+			if err != nil {
+				return err
+			}
+			if excluded {
+				if entry.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if entry.IsDir() {
+				return nil
+			}
+			included, err := matchesAnyGlob(include, entry.Name())
+			if err != nil {
+				return err
+			}
+			if included {
+				add(file)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+func matchesAnyGlob(globs []string, names ...string) (bool, error) {
+	for _, glob := range globs {
+		for _, name := range names {
+			ok, err := filepath.Match(glob, name)
+			if err != nil {
+				return false, fmt.Errorf("invalid glob '%s': %w", glob, err)
+			}
+			if ok {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
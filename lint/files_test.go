@@ -0,0 +1,85 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindDashboards(t *testing.T) {
+	dir := t.TempDir()
+	for _, f := range []string{
+		"a.json",
+		"b.yaml",
+		"nested/c.json",
+		"nested/deeper/d.json",
+		"vendor/e.json",
+		"nested/f.libsonnet.json",
+	} {
+		p := filepath.Join(dir, f)
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0700))
+		require.NoError(t, os.WriteFile(p, []byte("{}"), 0600))
+	}
+
+	for _, tc := range []struct {
+		desc     string
+		paths    []string
+		include  []string
+		exclude  []string
+		expected []string
+	}{
+		{
+			desc:    "Should walk directories recursively",
+			paths:   []string{dir},
+			include: []string{"*.json"},
+			expected: []string{
+				"a.json",
+				"nested/c.json",
+				"nested/deeper/d.json",
+				"nested/f.libsonnet.json",
+				"vendor/e.json",
+			},
+		},
+		{
+			desc:    "Should skip excluded files and directories",
+			paths:   []string{dir},
+			include: []string{"*.json"},
+			exclude: []string{"vendor", "*.libsonnet.json", "nested/deeper"},
+			expected: []string{
+				"a.json",
+				"nested/c.json",
+			},
+		},
+		{
+			desc:     "Should always return files given explicitly",
+			paths:    []string{filepath.Join(dir, "b.yaml"), filepath.Join(dir, "nested")},
+			include:  []string{"*.json"},
+			exclude:  []string{"*.libsonnet.json", "*.yaml"},
+			expected: []string{"b.yaml", "nested/c.json", "nested/deeper/d.json"},
+		},
+		{
+			desc:     "Should return each file once",
+			paths:    []string{filepath.Join(dir, "a.json"), dir},
+			include:  []string{"a.json"},
+			expected: []string{"a.json"},
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			files, err := FindDashboards(tc.paths, tc.include, tc.exclude)
+			require.NoError(t, err)
+
+			expected := make([]string, len(tc.expected))
+			for i, f := range tc.expected {
+				expected[i] = filepath.Join(dir, f)
+			}
+			require.Equal(t, expected, files)
+		})
+	}
+
+	t.Run("Should fail on missing paths", func(t *testing.T) {
+		_, err := FindDashboards([]string{filepath.Join(dir, "missing")}, nil, nil)
+		require.Error(t, err)
+	})
+}
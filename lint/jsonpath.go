@@ -0,0 +1,181 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type jsonPathStepKind int
+
+const (
+	// stepChild selects the member name of objects.
+	stepChild jsonPathStepKind = iota
+	// stepIndex selects the element index of arrays, counting from the end if negative.
+	stepIndex
+	// stepWildcard selects all members of objects, and all elements of arrays.
+	stepWildcard
+	// stepDescendant selects the member name of the node and all of its descendants, or all of them for *.
+	stepDescendant
+)
+
+type jsonPathStep struct {
+	kind  jsonPathStepKind
+	name  string
+	index int
+}
+
+// jsonPath is a compiled JSONPath expression. Only a subset of JSONPath is supported: the root $, children
+// .name and ['name'], indexes [0] and [-1], wildcards .* and [*], and recursive descent ..name and ..*.
+type jsonPath []jsonPathStep
+
+func compileJSONPath(s string) (jsonPath, error) {
+	if !strings.HasPrefix(s, "$") {
+		return nil, fmt.Errorf("must start with $")
+	}
+	var path jsonPath
+	rest := s[1:]
+	for rest != "" {
+		var step jsonPathStep
+		var err error
+		switch {
+		case strings.HasPrefix(rest, ".."):
+			var name string
+			name, rest = cutName(rest[2:])
+			if name == "" {
+				return nil, fmt.Errorf("missing name after '..'")
+			}
+			step = jsonPathStep{kind: stepDescendant, name: name}
+		case strings.HasPrefix(rest, "."):
+			var name string
+			name, rest = cutName(rest[1:])
+			switch name {
+			case "":
+				return nil, fmt.Errorf("missing name after '.'")
+			case "*":
+				step = jsonPathStep{kind: stepWildcard}
+			default:
+				step = jsonPathStep{kind: stepChild, name: name}
+			}
+		case strings.HasPrefix(rest, "["):
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("missing ']'")
+			}
+			step, err = bracketStep(rest[1:end])
+			if err != nil {
+				return nil, err
+			}
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("unexpected '%s'", rest)
+		}
+		path = append(path, step)
+	}
+	return path, nil
+}
+
+// cutName returns the name at the start of s, up to the next . or [, and the rest of s.
+func cutName(s string) (string, string) {
+	end := strings.IndexAny(s, ".[")
+	if end < 0 {
+		return s, ""
+	}
+	return s[:end], s[end:]
+}
+
+// bracketStep returns the step of the contents of brackets: *, a quoted name or an index.
+func bracketStep(s string) (jsonPathStep, error) {
+	if s == "*" {
+		return jsonPathStep{kind: stepWildcard}, nil
+	}
+	if len(s) >= 2 && (s[0] == '\'' || s[0] == '"') && s[len(s)-1] == s[0] {
+		return jsonPathStep{kind: stepChild, name: s[1 : len(s)-1]}, nil
+	}
+	i, err := strconv.Atoi(s)
+	if err != nil {
+		return jsonPathStep{}, fmt.Errorf("invalid index '%s'", s)
+	}
+	return jsonPathStep{kind: stepIndex, index: i}, nil
+}
+
+// eval returns the values selected from root, which is decoded like decodeTree does.
+func (p jsonPath) eval(root interface{}) []interface{} {
+	nodes := []interface{}{root}
+	for _, step := range p {
+		var next []interface{}
+		for _, n := range nodes {
+			next = append(next, step.eval(n)...)
+		}
+		nodes = next
+	}
+	return nodes
+}
+
+func (s jsonPathStep) eval(n interface{}) []interface{} {
+	switch s.kind {
+	case stepChild:
+		if obj, ok := n.(map[string]interface{}); ok {
+			if v, ok := obj[s.name]; ok {
+				return []interface{}{v}
+			}
+		}
+	case stepIndex:
+		if arr, ok := n.([]interface{}); ok {
+			i := s.index
+			if i < 0 {
+				i += len(arr)
+			}
+			if i >= 0 && i < len(arr) {
+				return []interface{}{arr[i]}
+			}
+		}
+	case stepWildcard:
+		return children(n)
+	case stepDescendant:
+		var found []interface{}
+		var walk func(n interface{})
+		walk = func(n interface{}) {
+			if s.name == "*" {
+				found = append(found, children(n)...)
+			} else {
+				found = append(found, jsonPathStep{kind: stepChild, name: s.name}.eval(n)...)
+			}
+			for _, c := range children(n) {
+				walk(c)
+			}
+		}
+		walk(n)
+		return found
+	}
+	return nil
+}
+
+// children returns the members of an object, in the order of their names, or the elements of an array.
+func children(n interface{}) []interface{} {
+	switch v := n.(type) {
+	case map[string]interface{}:
+		values := make([]interface{}, 0, len(v))
+		for _, k := range sortedKeys(v) {
+			values = append(values, v[k])
+		}
+		return values
+	case []interface{}:
+		return v
+	}
+	return nil
+}
+
+// jsonPathString returns a value selected by a JSONPath as a string: strings as they are, and anything else as
+// JSON.
+func jsonPathString(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	buf, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(buf)
+}
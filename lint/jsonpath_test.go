@@ -0,0 +1,63 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONPath(t *testing.T) {
+	root, err := decodeTree([]byte(`{
+		"title": "Sample",
+		"tags": ["team:a", "prod"],
+		"panels": [
+			{"title": "A", "targets": [{"expr": "up"}, {"expr": "rate(x[5m])"}]},
+			{"title": "B", "panels": [{"title": "C", "targets": [{"expr": "down"}]}]}
+		],
+		"o.dd": 1.5
+	}`))
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		path     string
+		expected []string
+	}{
+		{"$", []string{`{"o.dd":1.5,"panels":[{"targets":[{"expr":"up"},{"expr":"rate(x[5m])"}],"title":"A"},{"panels":[{"targets":[{"expr":"down"}],"title":"C"}],"title":"B"}],"tags":["team:a","prod"],"title":"Sample"}`}},
+		{"$.title", []string{"Sample"}},
+		{"$['title']", []string{"Sample"}},
+		{`$["o.dd"]`, []string{"1.5"}},
+		{"$.tags[*]", []string{"team:a", "prod"}},
+		{"$.tags.*", []string{"team:a", "prod"}},
+		{"$.tags[1]", []string{"prod"}},
+		{"$.tags[-1]", []string{"prod"}},
+		{"$.tags[2]", nil},
+		{"$.missing", nil},
+		{"$.title.missing", nil},
+		{"$.panels[*].title", []string{"A", "B"}},
+		{"$..title", []string{"Sample", "A", "B", "C"}},
+		{"$..expr", []string{"up", "rate(x[5m])", "down"}},
+		{"$.panels[0].targets..*", []string{`{"expr":"up"}`, `{"expr":"rate(x[5m])"}`, "up", "rate(x[5m])"}},
+	} {
+		t.Run(tc.path, func(t *testing.T) {
+			path, err := compileJSONPath(tc.path)
+			require.NoError(t, err)
+			var values []string
+			for _, v := range path.eval(root) {
+				values = append(values, jsonPathString(v))
+			}
+			require.Equal(t, tc.expected, values)
+		})
+	}
+
+	for path, expected := range map[string]string{
+		"title":     "must start with $",
+		"$.":        "missing name after '.'",
+		"$..":       "missing name after '..'",
+		"$.tags[0":  "missing ']'",
+		"$.tags[a]": "invalid index 'a'",
+		"$title":    "unexpected 'title'",
+	} {
+		_, err := compileJSONPath(path)
+		require.EqualError(t, err, expected, path)
+	}
+}
@@ -3,6 +3,7 @@ package lint
 import (
 	"encoding/json"
 	"fmt"
+	"slices"
 	"strings"
 )
 
@@ -12,6 +13,7 @@ const (
 	Success Severity = iota
 	Exclude
 	Quiet
+	Info
 	Warning
 	Error
 	Fixed
@@ -20,6 +22,31 @@ const (
 	Loki       = "loki"
 )
 
+func (s Severity) String() string {
+	switch s {
+	case Success:
+		return "success"
+	case Exclude:
+		return "excluded"
+	case Quiet:
+		return "quiet"
+	case Info:
+		return "info"
+	case Warning:
+		return "warning"
+	case Error:
+		return "error"
+	case Fixed:
+		return "fixed"
+	}
+	return fmt.Sprintf("Severity(%d)", int(s))
+}
+
+// MarshalText marshals the severity as its name, so it reads well in JSON.
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
 // Target is a deliberately incomplete representation of the Dashboard -> Template type in grafana.
 // The properties which are extracted from JSON are only those used for linting purposes.
 type Template struct {
@@ -34,6 +61,7 @@ type Template struct {
 	Current    RawTemplateValue   `json:"current"`
 	Options    []RawTemplateValue `json:"options"`
 	Refresh    int                `json:"refresh"`
+	Location   Location           `json:"-"` // This is set by NewDashboard
 	// If you add properties here don't forget to add them to the raw struct, and assign them from raw to actual in UnmarshalJSON below!
 }
 
@@ -177,6 +205,7 @@ type Target struct {
 	PanelId    int         `json:"panelId,omitempty"`
 	RefId      string      `json:"refId,omitempty"`
 	Hide       bool        `json:"hide"`
+	Location   Location    `json:"-"` // This is set by NewDashboard
 }
 
 func (t *Target) GetDataSource() (Datasource, error) {
@@ -186,6 +215,7 @@ func (t *Target) GetDataSource() (Datasource, error) {
 type Annotation struct {
 	Name       string      `json:"name"`
 	Datasource interface{} `json:"datasource,omitempty"`
+	Location   Location    `json:"-"` // This is set by NewDashboard
 }
 
 func (a *Annotation) GetDataSource() (Datasource, error) {
@@ -203,6 +233,16 @@ type Panel struct {
 	Type        string       `json:"type"`
 	Panels      []Panel      `json:"panels,omitempty"`
 	FieldConfig *FieldConfig `json:"fieldConfig,omitempty"`
+	Lint        *PanelLint   `json:"lint,omitempty"`
+	Location    Location     `json:"-"` // This is set by NewDashboard
+}
+
+// PanelLint is the configuration of the linter for a panel, kept in the panel itself.
+type PanelLint struct {
+	// Disable are the names, or globs, of the rules not to report problems of for the panel.
+	Disable []string `json:"disable,omitempty"`
+	// Reason is why the rules are disabled.
+	Reason string `json:"reason,omitempty"`
 }
 
 type FieldConfig struct {
@@ -246,6 +286,16 @@ type Defaults struct {
 	Unit string `json:"unit,omitempty"`
 }
 
+// appendPanelRefs appends pointers to the panel and all panels nested inside it to refs, in the same order as
+// GetPanels.
+func (p *Panel) appendPanelRefs(refs []*Panel) []*Panel {
+	refs = append(refs, p)
+	for i := range p.Panels {
+		refs = p.Panels[i].appendPanelRefs(refs)
+	}
+	return refs
+}
+
 // GetPanels returns the all panels nested inside the panel (inc the current panel)
 func (p *Panel) GetPanels() []Panel {
 	panels := []Panel{*p}
@@ -255,6 +305,17 @@ func (p *Panel) GetPanels() []Panel {
 	return panels
 }
 
+// locate sets the location of the panel, its targets and nested panels from the source it was parsed from.
+func (p *Panel) locate(src *source, pointer string) {
+	p.Location = src.location(pointer)
+	for i := range p.Targets {
+		p.Targets[i].Location = src.location(pointerJoin(pointer+"/targets", i))
+	}
+	for i := range p.Panels {
+		p.Panels[i].locate(src, pointerJoin(pointer+"/panels", i))
+	}
+}
+
 func (p *Panel) GetDataSource() (Datasource, error) {
 	return GetDataSource(p.Datasource)
 }
@@ -277,22 +338,30 @@ func (r *Row) GetPanels() []Panel {
 // Dashboard is a deliberately incomplete representation of the Dashboard type in grafana.
 // The properties which are extracted from JSON are only those used for linting purposes.
 type Dashboard struct {
-	Inputs     []Input `json:"__inputs"`
-	Title      string  `json:"title,omitempty"`
+	Inputs     []Input  `json:"__inputs"`
+	UID        string   `json:"uid,omitempty"`
+	Title      string   `json:"title,omitempty"`
+	Tags       []string `json:"tags,omitempty"`
 	Templating struct {
 		List []Template `json:"list"`
 	} `json:"templating"`
 	Annotations struct {
 		List []Annotation `json:"list"`
 	} `json:"annotations"`
-	Rows     []Row   `json:"rows,omitempty"`
-	Panels   []Panel `json:"panels,omitempty"`
-	Editable bool    `json:"editable,omitempty"`
+	Rows     []Row    `json:"rows,omitempty"`
+	Panels   []Panel  `json:"panels,omitempty"`
+	Editable bool     `json:"editable,omitempty"`
+	Location Location `json:"-"` // This is set by NewDashboard
+
+	source *source
+	// parsed caches the queries of the dashboard while it is being linted.
+	parsed *parseCache
 }
 
 // GetPanels returns the all panels whether they are nested in the (now deprecated) "rows" property or
 // in the top level "panels" property. This also monkeypatches Target.Idx into each panel which is used
-// to uniquely identify panel targets while linting.
+// to uniquely identify panel targets while linting. The targets are copied first, so the dashboard itself is
+// left untouched, and can be linted by several rules concurrently.
 func (d *Dashboard) GetPanels() []Panel {
 	var p []Panel
 	for _, row := range d.Rows {
@@ -301,14 +370,31 @@ func (d *Dashboard) GetPanels() []Panel {
 	for _, panel := range d.Panels {
 		p = append(p, panel.GetPanels()...)
 	}
-	for pi, pa := range p {
-		for ti := range pa.Targets {
+	for pi := range p {
+		p[pi].Targets = slices.Clone(p[pi].Targets)
+		for ti := range p[pi].Targets {
 			p[pi].Targets[ti].Idx = ti
 		}
 	}
 	return p
 }
 
+// panelRefs returns pointers to all panels, in the same order as GetPanels. Unlike the copies returned by
+// GetPanels, these can be used to modify a panel wherever it is nested: in a (deprecated) row, at the top
+// level, or inside a collapsed row panel.
+func (d *Dashboard) panelRefs() []*Panel {
+	var refs []*Panel
+	for ri := range d.Rows {
+		for pi := range d.Rows[ri].Panels {
+			refs = d.Rows[ri].Panels[pi].appendPanelRefs(refs)
+		}
+	}
+	for pi := range d.Panels {
+		refs = d.Panels[pi].appendPanelRefs(refs)
+	}
+	return refs
+}
+
 // GetTemplateByType returns all dashboard templates which match the provided type. Type comparison
 // is case insensitive as it uses strings.EqualFold()
 func (d *Dashboard) GetTemplateByType(t string) []Template {
@@ -321,8 +407,35 @@ func (d *Dashboard) GetTemplateByType(t string) []Template {
 	return retval
 }
 
+// Marshal returns the JSON representation of the dashboard. Dashboards created with NewDashboard are
+// marshalled losslessly: the JSON they were parsed from is returned, with only the values changed since
+// applied to it, so the key order, formatting and all the properties not part of the Dashboard type are kept.
 func (d *Dashboard) Marshal() ([]byte, error) {
-	return json.Marshal(d)
+	if d.source == nil {
+		return json.Marshal(d)
+	}
+	return d.source.marshal(d)
+}
+
+// locate sets the location of the dashboard and all the templates, annotations, panels and targets within it
+// from the source it was parsed from.
+func (d *Dashboard) locate(src *source) {
+	d.source = src
+	d.Location = src.location("")
+	for i := range d.Templating.List {
+		d.Templating.List[i].Location = src.location(pointerJoin("/templating/list", i))
+	}
+	for i := range d.Annotations.List {
+		d.Annotations.List[i].Location = src.location(pointerJoin("/annotations/list", i))
+	}
+	for i, row := range d.Rows {
+		for j := range row.Panels {
+			row.Panels[j].locate(src, pointerJoin(pointerJoin("/rows", i)+"/panels", j))
+		}
+	}
+	for i := range d.Panels {
+		d.Panels[i].locate(src, pointerJoin("/panels", i))
+	}
 }
 
 func NewDashboard(buf []byte) (Dashboard, error) {
@@ -330,5 +443,13 @@ func NewDashboard(buf []byte) (Dashboard, error) {
 	if err := json.Unmarshal(buf, &dash); err != nil {
 		return dash, err
 	}
+	src, err := newSource(buf)
+	if err != nil {
+		return dash, err
+	}
+	dash.locate(src)
+	if src.model, err = json.Marshal(dash); err != nil {
+		return dash, err
+	}
 	return dash, nil
 }
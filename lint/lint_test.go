@@ -1,6 +1,7 @@
 package lint
 
 import (
+	"bytes"
 	"strconv"
 	"testing"
 
@@ -20,6 +21,10 @@ func (r *TestRule) Name() string {
 	return r.name
 }
 
+func (r *TestRule) Metadata() RuleMetadata {
+	return RuleMetadata{Category: CategoryDashboard, Severity: Error}
+}
+
 func appendConfigExclude(t *testing.T, rule string, dashboard string, panel string, targetIdx string, config *ConfigurationFile) {
 	t.Helper()
 
@@ -112,6 +117,26 @@ func TestResultSet(t *testing.T) {
 		require.Len(t, byRule["rule2"], 1)
 	})
 
+	t.Run("Merge", func(t *testing.T) {
+		c := NewConfigurationFile()
+		other := ResultSet{}
+		other.Configure(c)
+		other.AddResult(newResultContext("rule1", "dash1", "", "", Error))
+
+		r := ResultSet{
+			results: []ResultContext{
+				newResultContext("rule1", "dash2", "", "", Warning),
+			},
+		}
+		r.Merge("dash1.json", &other)
+
+		require.Len(t, r.results, 2)
+		require.Equal(t, "", r.results[0].File)
+		require.Equal(t, "dash1.json", r.results[1].File)
+		require.Equal(t, Error, r.MaximumSeverity())
+		require.Same(t, c, r.config)
+	})
+
 	t.Run("Honors Configuration given config present before results added", func(t *testing.T) {
 		c := NewConfigurationFile()
 		appendConfigExclude(t, "rule1", "", "", "", c)
@@ -135,6 +160,20 @@ func TestResultSet(t *testing.T) {
 		require.Equal(t, Exclude, r.MaximumSeverity())
 		require.Equal(t, Exclude, r.ByRule()["rule1"][0].Result.Results[0].Severity)
 	})
+
+	t.Run("Reports the reason, owner and ticket when verbose", func(t *testing.T) {
+		c := NewConfigurationFile()
+		c.Verbose = true
+		c.Exclusions["rule1"] = &ConfigurationRuleEntries{Reason: "Legacy", Owner: "team-a", Ticket: "DASH-1"}
+
+		r := ResultSet{}
+		r.Configure(c)
+		r.AddResult(newResultContext("rule1", "", "", "", Error))
+
+		var buf bytes.Buffer
+		r.ReportByRuleTo(&buf)
+		require.Equal(t, "Test Rule\n[➖] foo (Excluded)\n    reason: Legacy, owner: team-a, ticket: DASH-1\n", buf.String())
+	})
 }
 
 func TestConfiguration(t *testing.T) {
@@ -214,6 +253,26 @@ func TestConfiguration(t *testing.T) {
 		}
 	})
 
+	t.Run("Records the reason of the matching entry", func(t *testing.T) {
+		c := NewConfigurationFile()
+		c.Exclusions["rule1"] = &ConfigurationRuleEntries{
+			Reason: "rule reason",
+			Entries: []ConfigurationEntry{
+				{Dashboard: "dash1", Reason: "dashboard reason"},
+				{Dashboard: "dash2"},
+			},
+		}
+
+		rc1 := c.Apply(newResultContext("rule1", "dash1", "", "", Error))
+		require.Equal(t, "dashboard reason", rc1.Reason)
+
+		rc2 := c.Apply(newResultContext("rule1", "dash2", "", "", Error))
+		require.Equal(t, "rule reason", rc2.Reason)
+
+		rc3 := c.Apply(newResultContext("rule1", "dash3", "", "", Error))
+		require.Equal(t, "", rc3.Reason)
+	})
+
 	// Dashboards
 	t.Run("Excludes Dashboard", func(t *testing.T) {
 		c := NewConfigurationFile()
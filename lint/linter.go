@@ -0,0 +1,231 @@
+package lint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"runtime"
+	"sync"
+)
+
+// Linter lints dashboards like the lint command does: with a set of rules, applying a configuration to the
+// results, and optionally fixing and reporting them. It is safe for concurrent use, the reports of concurrent
+// calls being written one after the other.
+type Linter struct {
+	rules    *RuleSet
+	config   *ConfigurationFile
+	fix      bool
+	jobs     int
+	reporter Reporter
+	out      io.Writer
+	// mu guards out, so reports don't interleave.
+	mu sync.Mutex
+}
+
+// LinterOption configures a Linter.
+type LinterOption func(*Linter)
+
+// WithRules sets the rules to lint with. By default, all rules, including the custom rules of the configuration,
+// are selected as set in the configuration.
+func WithRules(rules RuleSet) LinterOption {
+	return func(l *Linter) {
+		l.rules = &rules
+	}
+}
+
+// WithConfiguration sets the configuration applied to the results. By default, an empty configuration is.
+func WithConfiguration(config *ConfigurationFile) LinterOption {
+	return func(l *Linter) {
+		l.config = config
+	}
+}
+
+// WithFix makes the linter fix the problems it can. The fixed dashboards are returned, rather than written.
+func WithFix(fix bool) LinterOption {
+	return func(l *Linter) {
+		l.fix = fix
+	}
+}
+
+// WithJobs sets how many dashboards and rules are linted at once. It defaults to the number of CPUs.
+func WithJobs(jobs int) LinterOption {
+	return func(l *Linter) {
+		l.jobs = jobs
+	}
+}
+
+// WithReporter makes the linter report the results of every call linting dashboards to w.
+func WithReporter(reporter Reporter, w io.Writer) LinterOption {
+	return func(l *Linter) {
+		l.reporter = reporter
+		l.out = w
+	}
+}
+
+// NewLinter returns a Linter configured with opts.
+func NewLinter(opts ...LinterOption) *Linter {
+	l := &Linter{
+		jobs: runtime.NumCPU(),
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	if l.config == nil {
+		l.config = NewConfigurationFile()
+	}
+	return l
+}
+
+// LintResult is the outcome of linting a single dashboard.
+type LintResult struct {
+	// Name is the name the dashboard was read from, e.g. the path of its file.
+	Name string
+	// Results are the results of all rules, with the configuration applied.
+	Results *ResultSet
+	// Fixes is the number of problems fixed, and Fixed the fixed dashboard if there were any.
+	Fixes int
+	Fixed []byte
+}
+
+// LintResults are the outcomes of linting several dashboards.
+type LintResults []*LintResult
+
+// ResultSet returns the results of all dashboards in a single ResultSet.
+func (lr LintResults) ResultSet() *ResultSet {
+	rs := &ResultSet{}
+	for _, r := range lr {
+		rs.Merge(r.Name, r.Results)
+	}
+	return rs
+}
+
+// LintReader lints the dashboard read from r. The name of the dashboard is matched against the file of
+// configuration entries.
+func (l *Linter) LintReader(ctx context.Context, name string, r io.Reader) (*LintResult, error) {
+	res, err := l.lint(ctx, name, r)
+	if err != nil {
+		return nil, err
+	}
+	if err := l.report(res.Results); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// LintFS lints all dashboards in fsys whose name or slash separated path matches glob, like the --include flag
+// of the lint command. The dashboards which can't be linted are skipped, and returned as a single error along
+// with the results of all others. Once ctx is done, linting stops, and only the error of ctx is returned.
+func (l *Linter) LintFS(ctx context.Context, fsys fs.FS, glob string) (LintResults, error) {
+	if _, err := path.Match(glob, ""); err != nil {
+		return nil, fmt.Errorf("invalid glob '%s': %w", glob, err)
+	}
+	var names []string
+	err := fs.WalkDir(fsys, ".", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		if ok, _ := matchesAnyGlob([]string{glob}, entry.Name(), name); ok {
+			names = append(names, name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// dashboards are linted concurrently, but their results and errors are kept in order
+	all := make([]*LintResult, len(names))
+	errs := make([]error, len(names))
+	err = forEach(ctx, len(names), l.jobs, func(i int) {
+		f, err := fsys.Open(names[i])
+		if err != nil {
+			errs[i] = err
+			return
+		}
+		defer f.Close()
+		all[i], errs[i] = l.lint(ctx, names[i], f)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var results LintResults
+	for _, res := range all {
+		if res != nil {
+			results = append(results, res)
+		}
+	}
+	if err := l.report(results.ResultSet()); err != nil {
+		return nil, err
+	}
+	return results, errors.Join(errs...)
+}
+
+// lint lints the dashboard read from r, fixing it if configured to.
+func (l *Linter) lint(ctx context.Context, name string, r io.Reader) (*LintResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	buf, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dashboard %s: %w", name, err)
+	}
+	dashboard, err := NewDashboard(buf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse dashboard %s: %w", name, err)
+	}
+
+	rules := l.rules
+	if rules == nil {
+		all, err := l.config.AllRules()
+		if err != nil {
+			return nil, err
+		}
+		selected, err := all.Select(l.config.Selections()...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to select rules for dashboard %s: %w", name, err)
+		}
+		rules = &selected
+	}
+	results, err := rules.lintContext(ctx, []Dashboard{dashboard}, l.jobs)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &LintResult{Name: name, Results: &ResultSet{}}
+	if l.fix {
+		res.Fixes = results.AutoFix(&dashboard)
+		if res.Fixes > 0 {
+			if res.Fixed, err = dashboard.Marshal(); err != nil {
+				return nil, fmt.Errorf("failed to marshal fixed dashboard %s: %w", name, err)
+			}
+		}
+	}
+
+	// the name is recorded before configuring, so configuration entries can match it
+	res.Results.Merge(name, results)
+	res.Results.Configure(l.config)
+	return res, nil
+}
+
+// report reports rs if a reporter is set.
+func (l *Linter) report(rs *ResultSet) error {
+	if l.reporter == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := l.reporter.Report(l.out, rs); err != nil {
+		return fmt.Errorf("failed to report results: %w", err)
+	}
+	return nil
+}
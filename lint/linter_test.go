@@ -0,0 +1,128 @@
+package lint
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLinter(t *testing.T) {
+	sampleDashboard, err := os.ReadFile("testdata/dashboard.json")
+	require.NoError(t, err)
+
+	t.Run("Should lint dashboards read from readers", func(t *testing.T) {
+		config := NewConfigurationFile()
+		config.Exclusions["template-job-rule"] = &ConfigurationRuleEntries{
+			Entries: []ConfigurationEntry{{File: "dashboards/*.json"}},
+		}
+		linter := NewLinter(WithConfiguration(config))
+
+		res, err := linter.LintReader(context.Background(), "dashboards/sample.json", bytes.NewReader(sampleDashboard))
+		require.NoError(t, err)
+		require.Equal(t, "dashboards/sample.json", res.Name)
+		require.Equal(t, Error, res.Results.MaximumSeverity())
+		require.Nil(t, res.Fixed)
+		for _, rc := range res.Results.ByRule()["template-job-rule"] {
+			require.Equal(t, "dashboards/sample.json", rc.File)
+			for _, r := range rc.Result.Results {
+				require.Equal(t, Exclude, r.Severity)
+			}
+		}
+	})
+
+	t.Run("Should return fixed dashboards", func(t *testing.T) {
+		rules := RuleSet{}
+		rules.Add(NewUneditableRule())
+		linter := NewLinter(WithRules(rules), WithFix(true))
+
+		res, err := linter.LintReader(context.Background(), "sample.json", bytes.NewReader(sampleDashboard))
+		require.NoError(t, err)
+		require.Equal(t, 1, res.Fixes)
+		fixed, err := NewDashboard(res.Fixed)
+		require.NoError(t, err)
+		require.False(t, fixed.Editable)
+		require.Equal(t, Fixed, res.Results.MaximumSeverity())
+	})
+
+	t.Run("Should lint dashboards in file systems", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"b.json":                &fstest.MapFile{Data: sampleDashboard},
+			"a/dashboard.json":      &fstest.MapFile{Data: sampleDashboard},
+			"a/invalid.json":        &fstest.MapFile{Data: []byte("{")},
+			"a/README.md":           &fstest.MapFile{Data: []byte("# Dashboards")},
+			"a/nested/sample.json":  &fstest.MapFile{Data: sampleDashboard},
+			"a/nested/sample.jsonn": &fstest.MapFile{Data: sampleDashboard},
+		}
+		var report bytes.Buffer
+		reporter := ReporterFunc(func(w io.Writer, rs *ResultSet) error {
+			return rs.ReportJSON(w)
+		})
+		linter := NewLinter(WithJobs(4), WithReporter(reporter, &report))
+
+		results, err := linter.LintFS(context.Background(), fsys, "*.json")
+		require.ErrorContains(t, err, "failed to parse dashboard a/invalid.json")
+		var names []string
+		for _, res := range results {
+			names = append(names, res.Name)
+		}
+		require.Equal(t, []string{"a/dashboard.json", "a/nested/sample.json", "b.json"}, names)
+		for _, name := range names {
+			require.Contains(t, report.String(), `"file": "`+name+`"`, "should report the results of all dashboards")
+		}
+
+		results, err = linter.LintFS(context.Background(), fsys, "a/nested/*.json")
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+
+		_, err = linter.LintFS(context.Background(), fsys, "[")
+		require.ErrorContains(t, err, "invalid glob '['")
+	})
+
+	t.Run("Should not interleave the reports of concurrent calls", func(t *testing.T) {
+		var active atomic.Int32
+		var overlapped atomic.Bool
+		reporter := ReporterFunc(func(w io.Writer, rs *ResultSet) error {
+			if active.Add(1) > 1 {
+				overlapped.Store(true)
+			}
+			defer active.Add(-1)
+			time.Sleep(time.Millisecond)
+			return rs.ReportJSON(w)
+		})
+		var report bytes.Buffer
+		linter := NewLinter(WithJobs(1), WithReporter(reporter, &report))
+
+		var wg sync.WaitGroup
+		errs := make([]error, 8)
+		for i := range errs {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				_, errs[i] = linter.LintReader(context.Background(), "sample.json", bytes.NewReader(sampleDashboard))
+			}(i)
+		}
+		wg.Wait()
+		require.NoError(t, errors.Join(errs...))
+		require.False(t, overlapped.Load())
+	})
+
+	t.Run("Should stop once the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		linter := NewLinter()
+
+		_, err := linter.LintReader(ctx, "sample.json", bytes.NewReader(sampleDashboard))
+		require.ErrorIs(t, err, context.Canceled)
+		_, err = linter.LintFS(ctx, fstest.MapFS{"sample.json": &fstest.MapFile{Data: sampleDashboard}}, "*.json")
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
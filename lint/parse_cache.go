@@ -0,0 +1,65 @@
+package lint
+
+import (
+	"sync"
+
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+type parsed[T any] struct {
+	expr T
+	err  error
+}
+
+// parseCache holds the queries of a dashboard parsed with its variables expanded, so each query is parsed once
+// however many rules inspect it. It is safe for concurrent use, and parsed queries must not be modified.
+type parseCache struct {
+	mu     sync.Mutex
+	promQL map[string]parsed[parser.Expr]
+	logQL  map[string]parsed[syntax.Expr]
+}
+
+func newParseCache() *parseCache {
+	return &parseCache{
+		promQL: map[string]parsed[parser.Expr]{},
+		logQL:  map[string]parsed[syntax.Expr]{},
+	}
+}
+
+// cached returns the query expr from cache, parsing it with parse if it isn't in it yet.
+func cached[T any](mu *sync.Mutex, cache map[string]parsed[T], expr string, parse func() (T, error)) (T, error) {
+	mu.Lock()
+	p, ok := cache[expr]
+	mu.Unlock()
+	if !ok {
+		// queries are parsed outside of the lock, a query parsed twice concurrently gives the same result
+		p.expr, p.err = parse()
+		mu.Lock()
+		cache[expr] = p
+		mu.Unlock()
+	}
+	return p.expr, p.err
+}
+
+// parsePromQL returns expr parsed as PromQL with the variables of the dashboard expanded, like parsePromQL.
+// While the dashboard is being linted, each query is only parsed once.
+func (d Dashboard) parsePromQL(expr string) (parser.Expr, error) {
+	if d.parsed == nil {
+		return parsePromQL(expr, d.Templating.List)
+	}
+	return cached(&d.parsed.mu, d.parsed.promQL, expr, func() (parser.Expr, error) {
+		return parsePromQL(expr, d.Templating.List)
+	})
+}
+
+// parseLogQL returns expr parsed as LogQL with the variables of the dashboard expanded, like parseLogQL.
+// While the dashboard is being linted, each query is only parsed once.
+func (d Dashboard) parseLogQL(expr string) (syntax.Expr, error) {
+	if d.parsed == nil {
+		return parseLogQL(expr, d.Templating.List)
+	}
+	return cached(&d.parsed.mu, d.parsed.logQL, expr, func() (syntax.Expr, error) {
+		return parseLogQL(expr, d.Templating.List)
+	})
+}
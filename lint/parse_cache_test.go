@@ -0,0 +1,39 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCache(t *testing.T) {
+	d := Dashboard{
+		Templating: struct {
+			List []Template `json:"list"`
+		}{List: []Template{{Name: "job", Type: "query"}}},
+		parsed: newParseCache(),
+	}
+
+	first, err := d.parsePromQL(`sum(rate(up{job=~"$job"}[$__rate_interval]))`)
+	require.NoError(t, err)
+	second, err := d.parsePromQL(`sum(rate(up{job=~"$job"}[$__rate_interval]))`)
+	require.NoError(t, err)
+	require.Same(t, first, second, "queries should only be parsed once")
+	require.Len(t, d.parsed.promQL, 1)
+
+	_, err = d.parsePromQL(`sum(up`)
+	require.Error(t, err)
+	_, err = d.parsePromQL(`sum(up`)
+	require.Error(t, err, "errors should be cached too")
+
+	logs, err := d.parseLogQL(`sum(count_over_time({job="$job"}[$__auto]))`)
+	require.NoError(t, err)
+	again, err := d.parseLogQL(`sum(count_over_time({job="$job"}[$__auto]))`)
+	require.NoError(t, err)
+	require.Same(t, logs, again)
+
+	t.Run("Should parse without a cache", func(t *testing.T) {
+		_, err := Dashboard{}.parsePromQL(`up`)
+		require.NoError(t, err)
+	})
+}
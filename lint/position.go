@@ -0,0 +1,240 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Location identifies a node of the dashboard JSON it was parsed from.
+type Location struct {
+	// Pointer is the RFC 6901 JSON pointer of the node, e.g. /panels/3/targets/1.
+	Pointer string `json:"pointer"`
+	// Line and Column are the 1-based position of the start of the node in the source, counting
+	// columns in characters. They are zero if the position is unknown.
+	Line   int `json:"line,omitempty"`
+	Column int `json:"column,omitempty"`
+}
+
+func (l Location) String() string {
+	if l.Line == 0 {
+		return l.Pointer
+	}
+	return fmt.Sprintf("%d:%d (%s)", l.Line, l.Column, l.Pointer)
+}
+
+// span is the byte range [Start, End) of a JSON value in its source.
+type span struct {
+	Start, End int
+}
+
+// source is the original JSON document a dashboard was parsed from, along with the span of every value in it,
+// and the offset of the key of every object member, keyed by JSON pointer.
+type source struct {
+	buf        []byte
+	spans      map[string]span
+	keys       map[string]int
+	members    map[string][]string // the pointers of the members of each object, in source order
+	lineStarts []int
+	// model is the dashboard as marshalled right after parsing, to find what was changed since.
+	model []byte
+}
+
+func newSource(buf []byte) (*source, error) {
+	s := &source{
+		buf:        buf,
+		spans:      make(map[string]span),
+		keys:       make(map[string]int),
+		members:    make(map[string][]string),
+		lineStarts: []int{0},
+	}
+	for i, c := range buf {
+		if c == '\n' {
+			s.lineStarts = append(s.lineStarts, i+1)
+		}
+	}
+
+	sc := scanner{buf: buf, spans: s.spans, keys: s.keys, members: s.members}
+	if err := sc.value(""); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// location returns the location of the value at pointer, or just the pointer if it is not in the source.
+func (s *source) location(pointer string) Location {
+	if s == nil {
+		return Location{Pointer: pointer}
+	}
+	sp, ok := s.spans[pointer]
+	if !ok {
+		return Location{Pointer: pointer}
+	}
+	line := sort.Search(len(s.lineStarts), func(i int) bool { return s.lineStarts[i] > sp.Start })
+	start := s.lineStarts[line-1]
+	return Location{
+		Pointer: pointer,
+		Line:    line,
+		Column:  utf8.RuneCount(s.buf[start:sp.Start]) + 1,
+	}
+}
+
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// pointerJoin appends a reference token to a JSON pointer.
+func pointerJoin(pointer string, token interface{}) string {
+	switch t := token.(type) {
+	case int:
+		return pointer + "/" + strconv.Itoa(t)
+	case string:
+		return pointer + "/" + pointerEscaper.Replace(t)
+	}
+	panic(fmt.Sprintf("invalid JSON pointer token: %v", token))
+}
+
+// scanner records the span of every value, and the offset of every object key, of a JSON document. It is only
+// run on documents which have already been successfully unmarshalled, so it only checks for errors where it
+// could otherwise not make progress.
+type scanner struct {
+	buf     []byte
+	pos     int
+	spans   map[string]span
+	keys    map[string]int
+	members map[string][]string
+}
+
+func (s *scanner) skipWhitespace() {
+	for s.pos < len(s.buf) {
+		switch s.buf[s.pos] {
+		case ' ', '\t', '\n', '\r':
+			s.pos++
+		default:
+			return
+		}
+	}
+}
+
+func (s *scanner) expect(c byte) error {
+	s.skipWhitespace()
+	if s.pos >= len(s.buf) || s.buf[s.pos] != c {
+		return fmt.Errorf("expected '%c' at offset %d", c, s.pos)
+	}
+	s.pos++
+	return nil
+}
+
+func (s *scanner) value(pointer string) error {
+	s.skipWhitespace()
+	if s.pos >= len(s.buf) {
+		return fmt.Errorf("unexpected end of JSON input")
+	}
+	start := s.pos
+	var err error
+	switch s.buf[s.pos] {
+	case '{':
+		err = s.object(pointer)
+	case '[':
+		err = s.array(pointer)
+	case '"':
+		_, err = s.str()
+	default:
+		s.literal()
+	}
+	if err != nil {
+		return err
+	}
+	s.spans[pointer] = span{start, s.pos}
+	return nil
+}
+
+func (s *scanner) object(pointer string) error {
+	s.pos++ // {
+	s.skipWhitespace()
+	if s.pos < len(s.buf) && s.buf[s.pos] == '}' {
+		s.pos++
+		return nil
+	}
+	for {
+		s.skipWhitespace()
+		keyStart := s.pos
+		key, err := s.str()
+		if err != nil {
+			return err
+		}
+		if err := s.expect(':'); err != nil {
+			return err
+		}
+		member := pointerJoin(pointer, key)
+		s.keys[member] = keyStart
+		s.members[pointer] = append(s.members[pointer], member)
+		if err := s.value(member); err != nil {
+			return err
+		}
+		s.skipWhitespace()
+		if s.pos < len(s.buf) && s.buf[s.pos] == ',' {
+			s.pos++
+			continue
+		}
+		return s.expect('}')
+	}
+}
+
+func (s *scanner) array(pointer string) error {
+	s.pos++ // [
+	s.skipWhitespace()
+	if s.pos < len(s.buf) && s.buf[s.pos] == ']' {
+		s.pos++
+		return nil
+	}
+	for i := 0; ; i++ {
+		if err := s.value(pointerJoin(pointer, i)); err != nil {
+			return err
+		}
+		s.skipWhitespace()
+		if s.pos < len(s.buf) && s.buf[s.pos] == ',' {
+			s.pos++
+			continue
+		}
+		return s.expect(']')
+	}
+}
+
+// str scans a string and returns its unquoted value.
+func (s *scanner) str() (string, error) {
+	if s.pos >= len(s.buf) || s.buf[s.pos] != '"' {
+		return "", fmt.Errorf("expected string at offset %d", s.pos)
+	}
+	start := s.pos
+	escaped := false
+	for s.pos++; s.pos < len(s.buf); s.pos++ {
+		switch s.buf[s.pos] {
+		case '\\':
+			escaped = true
+			s.pos++
+		case '"':
+			s.pos++
+			raw := s.buf[start:s.pos]
+			if !escaped {
+				return string(raw[1 : len(raw)-1]), nil
+			}
+			var str string
+			err := json.Unmarshal(raw, &str)
+			return str, err
+		}
+	}
+	return "", fmt.Errorf("unterminated string at offset %d", start)
+}
+
+// literal scans a number, true, false or null.
+func (s *scanner) literal() {
+	for s.pos < len(s.buf) {
+		switch s.buf[s.pos] {
+		case ',', '}', ']', ' ', '\t', '\n', '\r':
+			return
+		}
+		s.pos++
+	}
+}
@@ -0,0 +1,90 @@
+package lint
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSourceLocation(t *testing.T) {
+	src, err := newSource([]byte("{\n  \"a/b\": [1, {\"c~\": \"é\\\"\"}],\n  \"d\": \"é\", \"e\": null\n}"))
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		pointer  string
+		expected Location
+	}{
+		{pointer: "", expected: Location{Pointer: "", Line: 1, Column: 1}},
+		{pointer: "/a~1b", expected: Location{Pointer: "/a~1b", Line: 2, Column: 10}},
+		{pointer: "/a~1b/0", expected: Location{Pointer: "/a~1b/0", Line: 2, Column: 11}},
+		{pointer: "/a~1b/1/c~0", expected: Location{Pointer: "/a~1b/1/c~0", Line: 2, Column: 21}},
+		{pointer: "/e", expected: Location{Pointer: "/e", Line: 3, Column: 18}},
+		{pointer: "/missing", expected: Location{Pointer: "/missing"}},
+	} {
+		require.Equal(t, tc.expected, src.location(tc.pointer), tc.pointer)
+	}
+
+	require.Equal(t, span{Start: 39, End: 43}, src.spans["/d"])
+}
+
+func TestDashboardLocations(t *testing.T) {
+	sampleDashboard, err := os.ReadFile("testdata/dashboard.json")
+	require.NoError(t, err)
+
+	d, err := NewDashboard(sampleDashboard)
+	require.NoError(t, err)
+
+	require.Equal(t, Location{Pointer: "", Line: 1, Column: 1}, d.Location)
+	require.Equal(t, Location{Pointer: "/annotations/list/0", Line: 13, Column: 7}, d.Annotations.List[0].Location)
+	require.Equal(t, Location{Pointer: "/templating/list/0", Line: 69, Column: 7}, d.Templating.List[0].Location)
+
+	panels := d.GetPanels()
+	require.Len(t, panels, 4)
+	for i, expected := range []struct {
+		panel  Location
+		target Location
+	}{
+		{
+			panel:  Location{Pointer: "/rows/0/panels/0", Line: 29, Column: 9},
+			target: Location{Pointer: "/rows/0/panels/0/targets/0", Line: 33, Column: 13},
+		},
+		{
+			panel:  Location{Pointer: "/panels/0", Line: 42, Column: 5},
+			target: Location{Pointer: "/panels/0/targets/0", Line: 46, Column: 9},
+		},
+		{
+			panel: Location{Pointer: "/panels/1", Line: 51, Column: 5},
+		},
+		{
+			panel:  Location{Pointer: "/panels/1/panels/0", Line: 55, Column: 9},
+			target: Location{Pointer: "/panels/1/panels/0/targets/0", Line: 59, Column: 13},
+		},
+	} {
+		require.Equal(t, expected.panel, panels[i].Location)
+		if expected.target != (Location{}) {
+			require.Equal(t, expected.target, panels[i].Targets[0].Location)
+		}
+	}
+
+	t.Run("Results carry the location", func(t *testing.T) {
+		rules := RuleSet{}
+		rules.Add(NewTargetRuleFunc("test-target-rule", "Test target rule",
+			func(d Dashboard, p Panel, t Target) TargetRuleResults {
+				return TargetRuleResults{}
+			},
+		))
+		rs, err := rules.Lint([]Dashboard{d})
+		require.NoError(t, err)
+
+		var locations []Location
+		for _, r := range rs.results {
+			locations = append(locations, r.Location)
+		}
+		require.Equal(t, []Location{
+			{Pointer: "/rows/0/panels/0/targets/0", Line: 33, Column: 13},
+			{Pointer: "/panels/0/targets/0", Line: 46, Column: 9},
+			{Pointer: "/panels/1/panels/0/targets/0", Line: 59, Column: 13},
+		}, locations)
+	})
+}
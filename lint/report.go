@@ -0,0 +1,166 @@
+package lint
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Reporter writes the results of linting to w, in some format.
+type Reporter interface {
+	Report(w io.Writer, rs *ResultSet) error
+}
+
+// ReporterFunc is a function used as a Reporter.
+type ReporterFunc func(w io.Writer, rs *ResultSet) error
+
+func (f ReporterFunc) Report(w io.Writer, rs *ResultSet) error {
+	return f(w, rs)
+}
+
+// GroupBy is what the results of a text report are grouped by.
+type GroupBy string
+
+const (
+	GroupByRule      GroupBy = "rule"
+	GroupByDashboard GroupBy = "dashboard"
+	GroupByFile      GroupBy = "file"
+)
+
+// stdinName is the name dashboards read from stdin are reported with.
+const stdinName = "<stdin>"
+
+// resultGroup is a group of the results of a report, with the heading it is reported under.
+type resultGroup struct {
+	heading string
+	results []ResultContext
+}
+
+// groups returns all results grouped by g, in the order they are reported in. Grouped by rule, the results are
+// ordered the same way as ByRule. Otherwise groups are ordered by dashboard title or file, and the results within
+// each by rule.
+func (rs *ResultSet) groups(g GroupBy) ([]resultGroup, error) {
+	if g == "" || g == GroupByRule {
+		byRule := rs.ByRule()
+		var groups []resultGroup
+		for _, rule := range sortedRules(byRule) {
+			groups = append(groups, resultGroup{heading: byRule[rule][0].Rule.Description(), results: byRule[rule]})
+		}
+		return groups, nil
+	}
+
+	var key func(rc ResultContext) string
+	switch g {
+	case GroupByDashboard:
+		key = func(rc ResultContext) string {
+			if rc.Dashboard == nil {
+				return ""
+			}
+			return rc.Dashboard.Title
+		}
+	case GroupByFile:
+		key = func(rc ResultContext) string {
+			if rc.File == "" {
+				return stdinName
+			}
+			return rc.File
+		}
+	default:
+		return nil, fmt.Errorf("unknown grouping '%s', must be one of rule, dashboard, file", g)
+	}
+
+	byKey := make(map[string][]ResultContext)
+	for _, rc := range rs.results {
+		byKey[key(rc)] = append(byKey[key(rc)], rc)
+	}
+	groups := make([]resultGroup, 0, len(byKey))
+	for _, k := range sortedKeys(byKey) {
+		results := byKey[k]
+		sort.SliceStable(results, func(i, j int) bool {
+			return results[i].Rule.Name() < results[j].Rule.Name()
+		})
+		heading := k
+		if g == GroupByDashboard {
+			heading = fmt.Sprintf("Dashboard '%s'", k)
+		}
+		groups = append(groups, resultGroup{heading: heading, results: results})
+	}
+	return groups, nil
+}
+
+// TextReporter reports results as text, under a heading for each group, with a symbol for the severity of each
+// result. It is the default output of the lint command.
+type TextReporter struct {
+	// Color colors the symbols of the severities with ANSI escape codes.
+	Color bool
+	// GroupBy is what the results are grouped by, by rule if empty.
+	GroupBy GroupBy
+}
+
+func (t TextReporter) Report(w io.Writer, rs *ResultSet) error {
+	groups, err := rs.groups(t.GroupBy)
+	if err != nil {
+		return err
+	}
+	for _, g := range groups {
+		if _, err := fmt.Fprintln(w, g.heading); err != nil {
+			return err
+		}
+		for _, rc := range g.results {
+			for _, r := range rc.Result.Results {
+				if !rs.isReported(r.Severity) {
+					continue
+				}
+				r.fprint(w, t.Color)
+				if rs.config != nil && rs.config.Verbose && r.Severity != Success {
+					rc.fprintConfiguration(w)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// CompactReporter reports one line per finding, starting with the file and position it was found at, in the
+// format of compilers, e.g.
+//
+//	dashboards/node.json:12:5: error: Dashboard 'Node' is editable, it should be set to 'editable: false' (template-uneditable-rule)
+//
+// Successes are left out.
+type CompactReporter struct {
+	// Color colors the severities with ANSI escape codes.
+	Color bool
+	// GroupBy is what the findings are ordered by, by rule if empty.
+	GroupBy GroupBy
+}
+
+func (c CompactReporter) Report(w io.Writer, rs *ResultSet) error {
+	groups, err := rs.groups(c.GroupBy)
+	if err != nil {
+		return err
+	}
+	for _, g := range groups {
+		for _, rc := range g.results {
+			file := rc.File
+			if file == "" {
+				file = stdinName
+			}
+			if rc.Location.Line > 0 {
+				file += fmt.Sprintf(":%d:%d", rc.Location.Line, rc.Location.Column)
+			}
+			for _, r := range rc.Result.Results {
+				if r.Severity == Success || !rs.isReported(r.Severity) {
+					continue
+				}
+				severity := r.Severity.String()
+				if color, ok := severityColors[r.Severity]; ok && c.Color {
+					severity = color + severity + colorReset
+				}
+				if _, err := fmt.Fprintf(w, "%s: %s: %s (%s)\n", file, severity, r.Message, rc.Rule.Name()); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package lint
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+var (
+	githubDataEscaper = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A")
+	githubPropEscaper = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C")
+)
+
+// ReportGitHubActions writes every error, warning and info to w as a GitHub Actions workflow command, so they
+// are shown as annotations on the files of a pull request.
+func (rs *ResultSet) ReportGitHubActions(w io.Writer) error {
+	byRule := rs.ByRule()
+	for _, rule := range sortedRules(byRule) {
+		for _, rc := range byRule[rule] {
+			for _, r := range rc.Result.Results {
+				var command string
+				switch r.Severity {
+				case Error:
+					command = "error"
+				case Warning:
+					command = "warning"
+				case Info:
+					command = "notice"
+				default:
+					continue
+				}
+
+				props := []string{}
+				if rc.File != "" {
+					props = append(props, "file="+githubPropEscaper.Replace(rc.File))
+					if rc.Location.Line > 0 {
+						props = append(props, fmt.Sprintf("line=%d,col=%d", rc.Location.Line, rc.Location.Column))
+					}
+				}
+				props = append(props, "title="+githubPropEscaper.Replace(rule))
+
+				if _, err := fmt.Fprintf(w, "::%s %s::%s\n", command, strings.Join(props, ","), githubDataEscaper.Replace(r.Message)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
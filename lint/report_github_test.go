@@ -0,0 +1,33 @@
+package lint
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportGitHubActions(t *testing.T) {
+	other := ResultSet{}
+	other.AddResult(ResultContext{
+		Rule: &TestRule{name: "rule1"},
+		Result: RuleResults{Results: []FixableResult{
+			{Result: Result{Severity: Error, Message: "100% broken\nreally"}},
+			{Result: Result{Severity: Success, Message: "OK"}},
+		}},
+	})
+	located := newResultContext("rule2", "dash1", "", "", Warning)
+	located.Location = Location{Pointer: "/panels/0", Line: 3, Column: 5}
+	other.AddResult(located)
+	other.AddResult(newResultContext("rule3", "dash1", "", "", Exclude))
+	other.AddResult(newResultContext("rule4", "dash1", "", "", Info))
+
+	rs := ResultSet{}
+	rs.Merge("dashboards/a,b.json", &other)
+
+	var buf bytes.Buffer
+	require.NoError(t, rs.ReportGitHubActions(&buf))
+	require.Equal(t, "::error file=dashboards/a%2Cb.json,title=rule1::100%25 broken%0Areally\n"+
+		"::warning file=dashboards/a%2Cb.json,line=3,col=5,title=rule2::foo\n"+
+		"::notice file=dashboards/a%2Cb.json,title=rule4::foo\n", buf.String())
+}
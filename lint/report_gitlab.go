@@ -0,0 +1,108 @@
+package lint
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+)
+
+type codeQualityIssue struct {
+	Description string              `json:"description"`
+	CheckName   string              `json:"check_name"`
+	Fingerprint string              `json:"fingerprint"`
+	Severity    string              `json:"severity"`
+	Location    codeQualityLocation `json:"location"`
+}
+
+type codeQualityLocation struct {
+	Path  string           `json:"path"`
+	Lines codeQualityLines `json:"lines"`
+}
+
+type codeQualityLines struct {
+	Begin int `json:"begin"`
+}
+
+// ReportGitLabCodeQuality writes every error, warning and info to w as a GitLab Code Quality report. Each issue has
+// a fingerprint derived from the rule, file, dashboard UID, panel id, target refId and the message of the rule, so
+// the same finding keeps the same fingerprint across runs, even if the dashboard or panel is renamed.
+func (rs *ResultSet) ReportGitLabCodeQuality(w io.Writer) error {
+	issues := []codeQualityIssue{}
+	seen := make(map[string]int)
+
+	byRule := rs.ByRule()
+	for _, rule := range sortedRules(byRule) {
+		for _, rc := range byRule[rule] {
+			for _, r := range rc.Result.Results {
+				var severity string
+				switch r.Severity {
+				case Error:
+					severity = "major"
+				case Warning:
+					severity = "minor"
+				case Info:
+					severity = "info"
+				default:
+					continue
+				}
+
+				fp := fingerprint(rc, r.Result)
+				// Identical findings still need unique fingerprints
+				seen[fp]++
+				if n := seen[fp]; n > 1 {
+					fp = fingerprintString(fmt.Sprintf("%s\x00%d", fp, n))
+				}
+
+				line := rc.Location.Line
+				if line == 0 {
+					line = 1
+				}
+				issues = append(issues, codeQualityIssue{
+					Description: r.Message,
+					CheckName:   rule,
+					Fingerprint: fp,
+					Severity:    severity,
+					Location: codeQualityLocation{
+						Path:  filepath.ToSlash(rc.File),
+						Lines: codeQualityLines{Begin: line},
+					},
+				})
+			}
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(issues)
+}
+
+// fingerprint identifies a finding independently of its severity, and of the titles and order of what it is about:
+// by its rule and file, the UID of the dashboard, the name of the template or annotation, the id of the panel and
+// the refId of the target, and the part of its message which comes from the rule.
+func fingerprint(rc ResultContext, r Result) string {
+	s := fmt.Sprintf("%s\x00%s", rc.Rule.Name(), filepath.ToSlash(rc.File))
+	if rc.Dashboard != nil {
+		s += fmt.Sprintf("\x00%s", rc.Dashboard.UID)
+	}
+	if rc.Template != nil {
+		s += fmt.Sprintf("\x00template\x00%s", rc.Template.Name)
+	}
+	if rc.Annotation != nil {
+		s += fmt.Sprintf("\x00annotation\x00%s", rc.Annotation.Name)
+	}
+	if rc.Panel != nil {
+		s += fmt.Sprintf("\x00%d", rc.Panel.Id)
+	}
+	if rc.Target != nil {
+		s += fmt.Sprintf("\x00%s", rc.Target.RefId)
+	}
+	return fingerprintString(s + "\x00" + ruleMessage(rc, r.Message))
+}
+
+func fingerprintString(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,78 @@
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportGitLabCodeQuality(t *testing.T) {
+	report := func(severity Severity) []codeQualityIssue {
+		other := ResultSet{}
+		other.AddResult(newResultContext("rule1", "dash1", "panel1", "0", severity))
+		other.AddResult(newResultContext("rule1", "dash1", "panel1", "0", severity))
+		other.AddResult(newResultContext("rule1", "dash1", "panel1", "1", severity))
+		other.AddResult(newResultContext("rule2", "dash1", "", "", Success))
+
+		rs := ResultSet{}
+		rs.Merge("dash1.json", &other)
+
+		var buf bytes.Buffer
+		require.NoError(t, rs.ReportGitLabCodeQuality(&buf))
+
+		var issues []codeQualityIssue
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &issues))
+		return issues
+	}
+
+	errors := report(Error)
+	require.Len(t, errors, 3)
+	for _, issue := range errors {
+		require.Equal(t, "rule1", issue.CheckName)
+		require.Equal(t, "major", issue.Severity)
+		require.Equal(t, "foo", issue.Description)
+		require.Equal(t, codeQualityLocation{Path: "dash1.json", Lines: codeQualityLines{Begin: 1}}, issue.Location)
+	}
+	require.NotEqual(t, errors[0].Fingerprint, errors[1].Fingerprint)
+	require.NotEqual(t, errors[0].Fingerprint, errors[2].Fingerprint)
+	require.NotEqual(t, errors[1].Fingerprint, errors[2].Fingerprint)
+
+	warnings := report(Warning)
+	require.Len(t, warnings, 3)
+	for i, issue := range warnings {
+		require.Equal(t, "minor", issue.Severity)
+		require.Equal(t, errors[i].Fingerprint, issue.Fingerprint, "fingerprints should not depend on severity")
+	}
+}
+
+func TestReportGitLabCodeQualityStableFingerprints(t *testing.T) {
+	report := func(dashboard, panel string, idx int) string {
+		d := Dashboard{UID: "uid1", Title: dashboard}
+		p := Panel{Id: 2, Title: panel}
+		target := Target{RefId: "B", Idx: idx}
+		other := ResultSet{}
+		other.AddResult(ResultContext{
+			Rule:      &TestRule{name: "rule1"},
+			Dashboard: &d,
+			Panel:     &p,
+			Target:    &target,
+			Result:    newRuleResults(Result{Severity: Error, Message: targetMessage(d, p, target, "is invalid")}),
+		})
+		rs := ResultSet{}
+		rs.Merge("dash1.json", &other)
+
+		var buf bytes.Buffer
+		require.NoError(t, rs.ReportGitLabCodeQuality(&buf))
+		var issues []codeQualityIssue
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &issues))
+		require.Len(t, issues, 1)
+		return issues[0].Fingerprint
+	}
+
+	fp := report("Node", "CPU", 0)
+	require.Equal(t, fp, report("Node exporter", "CPU", 0), "fingerprints should not depend on the title of the dashboard")
+	require.Equal(t, fp, report("Node", "CPU usage", 0), "fingerprints should not depend on the title of the panel")
+	require.Equal(t, fp, report("Node", "CPU", 1), "fingerprints should not depend on the order of the targets")
+}
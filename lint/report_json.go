@@ -0,0 +1,105 @@
+package lint
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// JSONReport is the machine-readable representation of a ResultSet written by ReportJSON.
+type JSONReport struct {
+	Results []JSONResult `json:"results"`
+}
+
+// JSONResult is a single result of a rule, along with the context it was found in.
+type JSONResult struct {
+	Rule       string          `json:"rule"`
+	Severity   string          `json:"severity"`
+	Message    string          `json:"message"`
+	File       string          `json:"file,omitempty"`
+	Location   *Location       `json:"location,omitempty"`
+	Dashboard  *JSONDashboard  `json:"dashboard,omitempty"`
+	Template   *JSONTemplate   `json:"template,omitempty"`
+	Annotation *JSONAnnotation `json:"annotation,omitempty"`
+	Panel      *JSONPanel      `json:"panel,omitempty"`
+	Target     *JSONTarget     `json:"target,omitempty"`
+	Fixable    bool            `json:"fixable"`
+	Fixed      bool            `json:"fixed"`
+}
+
+type JSONDashboard struct {
+	UID   string `json:"uid,omitempty"`
+	Title string `json:"title"`
+}
+
+type JSONTemplate struct {
+	Name string `json:"name"`
+}
+
+type JSONAnnotation struct {
+	Name string `json:"name"`
+}
+
+type JSONPanel struct {
+	ID    int    `json:"id"`
+	Title string `json:"title"`
+}
+
+type JSONTarget struct {
+	RefID string `json:"refId,omitempty"`
+	Idx   int    `json:"idx"`
+}
+
+// JSONReport returns all reported results, ordered the same way as ReportByRule.
+func (rs *ResultSet) JSONReport() JSONReport {
+	report := JSONReport{Results: []JSONResult{}}
+	byRule := rs.ByRule()
+	for _, rule := range sortedRules(byRule) {
+		for _, rc := range byRule[rule] {
+			for _, r := range rc.Result.Results {
+				if !rs.isReported(r.Severity) {
+					continue
+				}
+				report.Results = append(report.Results, newJSONResult(rc, r))
+			}
+		}
+	}
+	return report
+}
+
+// ReportJSON writes all reported results to w as a JSON document.
+func (rs *ResultSet) ReportJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(rs.JSONReport())
+}
+
+func newJSONResult(rc ResultContext, r FixableResult) JSONResult {
+	res := JSONResult{
+		Rule:     rc.Rule.Name(),
+		Severity: r.Severity.String(),
+		Message:  r.Message,
+		File:     rc.File,
+		Fixable:  r.Fix != nil,
+		Fixed:    r.Severity == Fixed,
+	}
+	if rc.Location != (Location{}) {
+		loc := rc.Location
+		res.Location = &loc
+	}
+	if rc.Dashboard != nil {
+		res.Dashboard = &JSONDashboard{UID: rc.Dashboard.UID, Title: rc.Dashboard.Title}
+	}
+	if rc.Template != nil {
+		res.Template = &JSONTemplate{Name: rc.Template.Name}
+	}
+	if rc.Annotation != nil {
+		res.Annotation = &JSONAnnotation{Name: rc.Annotation.Name}
+	}
+	if rc.Panel != nil {
+		res.Panel = &JSONPanel{ID: rc.Panel.Id, Title: rc.Panel.Title}
+	}
+	if rc.Target != nil {
+		res.Target = &JSONTarget{RefID: rc.Target.RefId, Idx: rc.Target.Idx}
+	}
+	return res
+}
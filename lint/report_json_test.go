@@ -0,0 +1,70 @@
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportJSON(t *testing.T) {
+	d := Dashboard{
+		UID:      "abc",
+		Title:    "dash",
+		Editable: true,
+		Panels: []Panel{
+			{
+				Id:    1,
+				Title: "panel",
+				Targets: []Target{
+					{RefId: "A"},
+				},
+			},
+		},
+	}
+
+	rules := RuleSet{}
+	rules.Add(NewUneditableRule())
+	rules.Add(NewTargetRuleFunc("test-target-rule", "Test target rule",
+		func(d Dashboard, p Panel, t Target) TargetRuleResults {
+			r := TargetRuleResults{}
+			r.AddError(d, p, t, "is broken")
+			return r
+		},
+	))
+	rules.Add(NewPanelRuleFunc("test-panel-rule", "Test panel rule",
+		func(Dashboard, Panel) PanelRuleResults {
+			return PanelRuleResults{}
+		},
+	))
+
+	rs, err := rules.Lint([]Dashboard{d})
+	require.NoError(t, err)
+	rs.AutoFix(&d)
+	rs.Configure(NewConfigurationFile())
+
+	var buf bytes.Buffer
+	require.NoError(t, rs.ReportJSON(&buf))
+
+	var report JSONReport
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &report))
+	require.Equal(t, JSONReport{Results: []JSONResult{
+		{
+			Rule:      "test-target-rule",
+			Severity:  "error",
+			Message:   "Dashboard 'dash', panel 'panel', target idx '0' is broken",
+			Dashboard: &JSONDashboard{UID: "abc", Title: "dash"},
+			Panel:     &JSONPanel{ID: 1, Title: "panel"},
+			Target:    &JSONTarget{RefID: "A", Idx: 0},
+		},
+		{
+			Rule:      "uneditable-dashboard",
+			Severity:  "fixed",
+			Message:   "Dashboard 'dash' is editable, it should be set to 'editable: false'",
+			Dashboard: &JSONDashboard{UID: "abc", Title: "dash"},
+			Fixable:   true,
+			Fixed:     true,
+		},
+	}}, report)
+}
@@ -0,0 +1,164 @@
+package lint
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	File     string          `xml:"file,attr,omitempty"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Skipped  int             `xml:"skipped,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	File      string        `xml:"file,attr,omitempty"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Body    string `xml:",chardata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr,omitempty"`
+}
+
+// ReportJUnit writes all results to w as a JUnit XML report. Every dashboard is reported as a test suite, and
+// every rule result context within it as a test case. Errors are reported as failures, and excluded results
+// as skipped along with the reason for the exclusion. Warnings are reported as failures if warningsAsFailures
+// is set, and as skipped otherwise.
+func (rs *ResultSet) ReportJUnit(w io.Writer, warningsAsFailures bool) error {
+	report := junitTestSuites{Name: "dashboard-linter"}
+	suites := make(map[string]int)
+
+	for _, rc := range rs.results {
+		title := ""
+		if rc.Dashboard != nil {
+			title = rc.Dashboard.Title
+		}
+		key := rc.File + "\x00" + title
+		si, ok := suites[key]
+		if !ok {
+			si = len(report.Suites)
+			suites[key] = si
+			report.Suites = append(report.Suites, junitTestSuite{Name: title, File: rc.File})
+		}
+		suite := &report.Suites[si]
+
+		tc := newJUnitTestCase(rc, warningsAsFailures)
+		suite.Cases = append(suite.Cases, tc)
+		suite.Tests++
+		report.Tests++
+		if tc.Failure != nil {
+			suite.Failures++
+			report.Failures++
+		}
+		if tc.Skipped != nil {
+			suite.Skipped++
+			report.Skipped++
+		}
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func newJUnitTestCase(rc ResultContext, warningsAsFailures bool) junitTestCase {
+	tc := junitTestCase{
+		Name:      junitTestCaseName(rc),
+		ClassName: rc.Rule.Name(),
+		File:      rc.File,
+	}
+
+	var failures, warnings []string
+	excluded := false
+	for _, r := range rc.Result.Results {
+		switch r.Severity {
+		case Error:
+			failures = append(failures, r.Message)
+		case Warning:
+			warnings = append(warnings, r.Message)
+		case Exclude:
+			excluded = true
+		}
+	}
+
+	switch {
+	case len(failures) > 0:
+		tc.Failure = &junitFailure{
+			Message: failures[0],
+			Type:    Error.String(),
+			Body:    strings.Join(append(failures, warnings...), "\n"),
+		}
+	case len(warnings) > 0 && warningsAsFailures:
+		tc.Failure = &junitFailure{
+			Message: warnings[0],
+			Type:    Warning.String(),
+			Body:    strings.Join(warnings, "\n"),
+		}
+	case len(warnings) > 0:
+		tc.Skipped = &junitSkipped{Message: warnings[0]}
+	case excluded:
+		tc.Skipped = &junitSkipped{Message: rc.Reason}
+		if tc.Skipped.Message == "" {
+			tc.Skipped.Message = "excluded by configuration"
+		}
+	}
+	return tc
+}
+
+func junitTestCaseName(rc ResultContext) string {
+	var context []string
+	if rc.Template != nil {
+		context = append(context, fmt.Sprintf("template '%s'", rc.Template.Name))
+	}
+	if rc.Annotation != nil {
+		context = append(context, fmt.Sprintf("annotation '%s'", rc.Annotation.Name))
+	}
+	if rc.Panel != nil {
+		if rc.Panel.Title != "" {
+			context = append(context, fmt.Sprintf("panel '%s'", rc.Panel.Title))
+		} else {
+			context = append(context, fmt.Sprintf("panel with id '%d'", rc.Panel.Id))
+		}
+	}
+	if rc.Target != nil {
+		if rc.Target.RefId != "" {
+			context = append(context, fmt.Sprintf("target '%s'", rc.Target.RefId))
+		} else {
+			context = append(context, fmt.Sprintf("target idx '%d'", rc.Target.Idx))
+		}
+	}
+	if len(context) == 0 {
+		return rc.Rule.Name()
+	}
+	return fmt.Sprintf("%s (%s)", rc.Rule.Name(), strings.Join(context, ", "))
+}
@@ -0,0 +1,80 @@
+package lint
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportJUnit(t *testing.T) {
+	c := NewConfigurationFile()
+	c.Exclusions["rule3"] = &ConfigurationRuleEntries{Reason: "not applicable"}
+
+	other := ResultSet{}
+	other.AddResult(newResultContext("rule1", "dash1", "panel1", "0", Error))
+	other.AddResult(newResultContext("rule2", "dash1", "panel1", "", Warning))
+	other.AddResult(newResultContext("rule3", "dash1", "", "", Error))
+	other.AddResult(newResultContext("rule4", "dash2", "", "", Success))
+	other.Configure(c)
+
+	rs := ResultSet{}
+	rs.Merge("dash.json", &other)
+
+	for _, tc := range []struct {
+		desc               string
+		warningsAsFailures bool
+		expected           string
+	}{
+		{
+			desc:               "Should skip warnings",
+			warningsAsFailures: false,
+			expected: `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="dashboard-linter" tests="4" failures="1" skipped="2">
+  <testsuite name="dash1" file="dash.json" tests="3" failures="1" skipped="2">
+    <testcase name="rule1 (panel &#39;panel1&#39;, target idx &#39;0&#39;)" classname="rule1" file="dash.json">
+      <failure message="foo" type="error">foo</failure>
+    </testcase>
+    <testcase name="rule2 (panel &#39;panel1&#39;)" classname="rule2" file="dash.json">
+      <skipped message="foo"></skipped>
+    </testcase>
+    <testcase name="rule3" classname="rule3" file="dash.json">
+      <skipped message="not applicable"></skipped>
+    </testcase>
+  </testsuite>
+  <testsuite name="dash2" file="dash.json" tests="1" failures="0" skipped="0">
+    <testcase name="rule4" classname="rule4" file="dash.json"></testcase>
+  </testsuite>
+</testsuites>
+`,
+		},
+		{
+			desc:               "Should fail warnings",
+			warningsAsFailures: true,
+			expected: `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="dashboard-linter" tests="4" failures="2" skipped="1">
+  <testsuite name="dash1" file="dash.json" tests="3" failures="2" skipped="1">
+    <testcase name="rule1 (panel &#39;panel1&#39;, target idx &#39;0&#39;)" classname="rule1" file="dash.json">
+      <failure message="foo" type="error">foo</failure>
+    </testcase>
+    <testcase name="rule2 (panel &#39;panel1&#39;)" classname="rule2" file="dash.json">
+      <failure message="foo" type="warning">foo</failure>
+    </testcase>
+    <testcase name="rule3" classname="rule3" file="dash.json">
+      <skipped message="not applicable"></skipped>
+    </testcase>
+  </testsuite>
+  <testsuite name="dash2" file="dash.json" tests="1" failures="0" skipped="0">
+    <testcase name="rule4" classname="rule4" file="dash.json"></testcase>
+  </testsuite>
+</testsuites>
+`,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, rs.ReportJUnit(&buf, tc.warningsAsFailures))
+			require.Equal(t, tc.expected, buf.String())
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package lint
+
+import (
+	"encoding/json"
+	"io"
+	"path/filepath"
+)
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+
+	informationURL = "https://github.com/grafana/dashboard-linter"
+	rulesDocsURL   = informationURL + "/blob/main/docs/rules/"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string                     `json:"name"`
+	InformationURI string                     `json:"informationUri"`
+	Rules          []sarifReportingDescriptor `json:"rules"`
+}
+
+type sarifReportingDescriptor struct {
+	ID                   string             `json:"id"`
+	Name                 string             `json:"name"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	HelpURI              string             `json:"helpUri,omitempty"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+	Properties           sarifProperties    `json:"properties"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifProperties struct {
+	Tags []string `json:"tags"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID       string             `json:"ruleId"`
+	RuleIndex    int                `json:"ruleIndex"`
+	Level        string             `json:"level"`
+	Message      sarifMessage       `json:"message"`
+	Locations    []sarifLocation    `json:"locations,omitempty"`
+	Suppressions []sarifSuppression `json:"suppressions,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation  `json:"physicalLocation"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifSuppression struct {
+	Kind          string `json:"kind"`
+	Justification string `json:"justification,omitempty"`
+}
+
+// ReportSARIF writes all findings to w as a SARIF 2.1.0 log. Every rule in rules is described in the log,
+// along with any other rule which has results in the ResultSet. Successful results are omitted, excluded
+// results are reported as suppressed, in source if they were suppressed inline in the dashboard.
+func (rs *ResultSet) ReportSARIF(w io.Writer, rules []Rule) error {
+	driver := sarifDriver{
+		Name:           "dashboard-linter",
+		InformationURI: informationURL,
+		Rules:          []sarifReportingDescriptor{},
+	}
+	ruleIndex := make(map[string]int)
+	addRule := func(r Rule) {
+		if _, ok := ruleIndex[r.Name()]; ok {
+			return
+		}
+		ruleIndex[r.Name()] = len(driver.Rules)
+		metadata := r.Metadata()
+		driver.Rules = append(driver.Rules, sarifReportingDescriptor{
+			ID:                   r.Name(),
+			Name:                 r.Name(),
+			ShortDescription:     sarifMessage{Text: r.Description()},
+			HelpURI:              metadata.DocsURL,
+			DefaultConfiguration: sarifConfiguration{Level: sarifLevel(metadata.Severity)},
+			Properties:           sarifProperties{Tags: []string{metadata.Category}},
+		})
+	}
+	for _, r := range rules {
+		addRule(r)
+	}
+
+	results := []sarifResult{}
+	byRule := rs.ByRule()
+	for _, rule := range sortedRules(byRule) {
+		for _, rc := range byRule[rule] {
+			addRule(rc.Rule)
+			for _, r := range rc.Result.Results {
+				level := sarifLevel(r.Severity)
+				if level == "" {
+					continue
+				}
+				res := sarifResult{
+					RuleID:    rule,
+					RuleIndex: ruleIndex[rule],
+					Level:     level,
+					Message:   sarifMessage{Text: r.Message},
+				}
+				if rc.File != "" {
+					res.Locations = []sarifLocation{newSARIFLocation(rc.File, rc.Location)}
+				}
+				if r.Severity == Exclude {
+					suppression := sarifSuppression{Kind: "external", Justification: rc.Reason}
+					if rc.Suppression != "" {
+						suppression.Kind = "inSource"
+					}
+					res.Suppressions = []sarifSuppression{suppression}
+				}
+				results = append(results, res)
+			}
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs: []sarifRun{{
+			Tool:    sarifTool{Driver: driver},
+			Results: results,
+		}},
+	})
+}
+
+func newSARIFLocation(file string, l Location) sarifLocation {
+	loc := sarifLocation{
+		PhysicalLocation: sarifPhysicalLocation{
+			ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(file)},
+		},
+	}
+	if l.Line > 0 {
+		loc.PhysicalLocation.Region = &sarifRegion{StartLine: l.Line, StartColumn: l.Column}
+	}
+	if l.Pointer != "" {
+		loc.LogicalLocations = []sarifLogicalLocation{{FullyQualifiedName: l.Pointer}}
+	}
+	return loc
+}
+
+// sarifLevel returns the SARIF level of a result with the given severity, or an empty string if it should
+// not be reported at all.
+func sarifLevel(s Severity) string {
+	switch s {
+	case Error:
+		return "error"
+	case Warning:
+		return "warning"
+	case Info, Exclude, Fixed:
+		return "note"
+	}
+	return ""
+}
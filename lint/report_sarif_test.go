@@ -0,0 +1,104 @@
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportSARIF(t *testing.T) {
+	c := NewConfigurationFile()
+	appendConfigExclude(t, "rule2", "dash1", "", "", c)
+
+	other := ResultSet{}
+	other.AddResult(newResultContext("rule1", "dash1", "", "", Error))
+	other.AddResult(newResultContext("rule2", "dash1", "", "", Warning))
+	other.AddResult(newResultContext("rule3", "dash1", "", "", Success))
+	other.Configure(c)
+
+	rs := ResultSet{}
+	rs.Merge("dashboards/dash1.json", &other)
+
+	var buf bytes.Buffer
+	require.NoError(t, rs.ReportSARIF(&buf, []Rule{NewUneditableRule()}))
+
+	var log sarifLog
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &log))
+
+	require.Equal(t, "2.1.0", log.Version)
+	require.Len(t, log.Runs, 1)
+	run := log.Runs[0]
+
+	require.Equal(t, []sarifReportingDescriptor{
+		{
+			ID:                   "uneditable-dashboard",
+			Name:                 "uneditable-dashboard",
+			ShortDescription:     sarifMessage{Text: "Checks that the dashboard is not editable."},
+			HelpURI:              "https://github.com/grafana/dashboard-linter/blob/main/docs/rules/template-uneditable-rule.md",
+			DefaultConfiguration: sarifConfiguration{Level: "error"},
+			Properties:           sarifProperties{Tags: []string{"dashboard"}},
+		},
+		{
+			ID:                   "rule1",
+			Name:                 "rule1",
+			ShortDescription:     sarifMessage{Text: "Test Rule"},
+			DefaultConfiguration: sarifConfiguration{Level: "error"},
+			Properties:           sarifProperties{Tags: []string{"dashboard"}},
+		},
+		{
+			ID:                   "rule2",
+			Name:                 "rule2",
+			ShortDescription:     sarifMessage{Text: "Test Rule"},
+			DefaultConfiguration: sarifConfiguration{Level: "error"},
+			Properties:           sarifProperties{Tags: []string{"dashboard"}},
+		},
+		{
+			ID:                   "rule3",
+			Name:                 "rule3",
+			ShortDescription:     sarifMessage{Text: "Test Rule"},
+			DefaultConfiguration: sarifConfiguration{Level: "error"},
+			Properties:           sarifProperties{Tags: []string{"dashboard"}},
+		},
+	}, run.Tool.Driver.Rules)
+
+	location := []sarifLocation{{
+		PhysicalLocation: sarifPhysicalLocation{
+			ArtifactLocation: sarifArtifactLocation{URI: "dashboards/dash1.json"},
+		},
+	}}
+	require.Equal(t, []sarifResult{
+		{
+			RuleID:    "rule1",
+			RuleIndex: 1,
+			Level:     "error",
+			Message:   sarifMessage{Text: "foo"},
+			Locations: location,
+		},
+		{
+			RuleID:       "rule2",
+			RuleIndex:    2,
+			Level:        "note",
+			Message:      sarifMessage{Text: "foo (Excluded)"},
+			Locations:    location,
+			Suppressions: []sarifSuppression{{Kind: "external"}},
+		},
+	}, run.Results)
+}
+
+func TestReportSARIFInlineSuppression(t *testing.T) {
+	res := newResultContext("rule1", "dash1", "", "", Error)
+	res.Dashboard.Tags = []string{"lint:disable=rule1"}
+	rs := ResultSet{}
+	rs.Configure(NewConfigurationFile())
+	rs.AddResult(res)
+
+	var buf bytes.Buffer
+	require.NoError(t, rs.ReportSARIF(&buf, nil))
+
+	var log sarifLog
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &log))
+	require.Len(t, log.Runs[0].Results, 1)
+	require.Equal(t, []sarifSuppression{{Kind: "inSource"}}, log.Runs[0].Results[0].Suppressions)
+}
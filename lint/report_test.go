@@ -0,0 +1,68 @@
+package lint
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReporters(t *testing.T) {
+	other := ResultSet{}
+	other.AddResult(newResultContext("rule2", "dash1", "", "", Error))
+	other.AddResult(newResultContext("rule1", "dash1", "", "", Warning))
+	rs := ResultSet{}
+	rs.Merge("dashboards/dash1.json", &other)
+	stdin := ResultSet{}
+	stdin.AddResult(newResultContext("rule1", "dash0", "", "", Info))
+	stdin.AddResult(newResultContext("rule1", "dash0", "", "", Success))
+	rs.Merge("", &stdin)
+
+	for _, tc := range []struct {
+		desc     string
+		reporter Reporter
+		expected string
+	}{
+		{
+			desc:     "Should group by rule",
+			reporter: TextReporter{},
+			expected: "Test Rule\n[ℹ️] foo\n[✔️] foo\n[⚠️] foo\nTest Rule\n[❌] foo\n",
+		},
+		{
+			desc:     "Should group by dashboard",
+			reporter: TextReporter{GroupBy: GroupByDashboard},
+			expected: "Dashboard 'dash0'\n[ℹ️] foo\n[✔️] foo\nDashboard 'dash1'\n[⚠️] foo\n[❌] foo\n",
+		},
+		{
+			desc:     "Should group by file",
+			reporter: TextReporter{GroupBy: GroupByFile},
+			expected: "<stdin>\n[ℹ️] foo\n[✔️] foo\ndashboards/dash1.json\n[⚠️] foo\n[❌] foo\n",
+		},
+		{
+			desc:     "Should color",
+			reporter: TextReporter{Color: true, GroupBy: GroupByFile},
+			expected: "<stdin>\n[\033[34mℹ️\033[0m] foo\n[\033[32m✔️\033[0m] foo\ndashboards/dash1.json\n[\033[33m⚠️\033[0m] foo\n[\033[31m❌\033[0m] foo\n",
+		},
+		{
+			desc:     "Should report one line per finding",
+			reporter: CompactReporter{},
+			expected: "<stdin>: info: foo (rule1)\ndashboards/dash1.json: warning: foo (rule1)\ndashboards/dash1.json: error: foo (rule2)\n",
+		},
+		{
+			desc:     "Should color compact findings",
+			reporter: CompactReporter{Color: true, GroupBy: GroupByDashboard},
+			expected: "<stdin>: \033[34minfo\033[0m: foo (rule1)\ndashboards/dash1.json: \033[33mwarning\033[0m: foo (rule1)\ndashboards/dash1.json: \033[31merror\033[0m: foo (rule2)\n",
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, tc.reporter.Report(&buf, &rs))
+			require.Equal(t, tc.expected, buf.String())
+		})
+	}
+
+	t.Run("Should reject unknown groupings", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.EqualError(t, TextReporter{GroupBy: "panel"}.Report(&buf, &rs), "unknown grouping 'panel', must be one of rule, dashboard, file")
+	})
+}
@@ -2,8 +2,10 @@ package lint
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"sort"
+	"strings"
 )
 
 var ResultSuccess = Result{
@@ -34,11 +36,87 @@ type TargetRuleResults struct {
 	Results []TargetResult
 }
 
+func targetMessage(d Dashboard, p Panel, t Target, message string) string {
+	return fmt.Sprintf("Dashboard '%s', panel '%s', target idx '%d' %s", d.Title, p.Title, t.Idx, message)
+}
+
 func (r *TargetRuleResults) AddError(d Dashboard, p Panel, t Target, message string) {
 	r.Results = append(r.Results, TargetResult{
 		Result: Result{
 			Severity: Error,
-			Message:  fmt.Sprintf("Dashboard '%s', panel '%s', target idx '%d' %s", d.Title, p.Title, t.Idx, message),
+			Message:  targetMessage(d, p, t, message),
+		},
+	})
+}
+
+type TemplateResult struct {
+	Result
+	Fix func(Dashboard, *Template)
+}
+
+type TemplateRuleResults struct {
+	Results []TemplateResult
+}
+
+func templateMessage(d Dashboard, t Template, message string) string {
+	return fmt.Sprintf("Dashboard '%s' template '%s' %s", d.Title, t.Name, message)
+}
+
+func (r *TemplateRuleResults) AddError(d Dashboard, t Template, message string) {
+	r.Results = append(r.Results, TemplateResult{
+		Result: Result{
+			Severity: Error,
+			Message:  templateMessage(d, t, message),
+		},
+	})
+}
+
+func (r *TemplateRuleResults) AddFixableError(d Dashboard, t Template, message string, fix func(Dashboard, *Template)) {
+	r.Results = append(r.Results, TemplateResult{
+		Result: Result{
+			Severity: Error,
+			Message:  templateMessage(d, t, message),
+		},
+		Fix: fix,
+	})
+}
+
+func (r *TemplateRuleResults) AddWarning(d Dashboard, t Template, message string) {
+	r.Results = append(r.Results, TemplateResult{
+		Result: Result{
+			Severity: Warning,
+			Message:  templateMessage(d, t, message),
+		},
+	})
+}
+
+type AnnotationResult struct {
+	Result
+	Fix func(Dashboard, *Annotation)
+}
+
+type AnnotationRuleResults struct {
+	Results []AnnotationResult
+}
+
+func annotationMessage(d Dashboard, a Annotation, message string) string {
+	return fmt.Sprintf("Dashboard '%s' annotation '%s' %s", d.Title, a.Name, message)
+}
+
+func (r *AnnotationRuleResults) AddError(d Dashboard, a Annotation, message string) {
+	r.Results = append(r.Results, AnnotationResult{
+		Result: Result{
+			Severity: Error,
+			Message:  annotationMessage(d, a, message),
+		},
+	})
+}
+
+func (r *AnnotationRuleResults) AddWarning(d Dashboard, a Annotation, message string) {
+	r.Results = append(r.Results, AnnotationResult{
+		Result: Result{
+			Severity: Warning,
+			Message:  annotationMessage(d, a, message),
 		},
 	})
 }
@@ -52,16 +130,18 @@ type PanelRuleResults struct {
 	Results []PanelResult
 }
 
-func (r *PanelRuleResults) AddError(d Dashboard, p Panel, message string) {
-	msg := fmt.Sprintf("Dashboard '%s', panel '%s' %s", d.Title, p.Title, message)
+func panelMessage(d Dashboard, p Panel, message string) string {
 	if p.Title == "" {
-		msg = fmt.Sprintf("Dashboard '%s', panel with id '%d' %s", d.Title, p.Id, message)
+		return fmt.Sprintf("Dashboard '%s', panel with id '%d' %s", d.Title, p.Id, message)
 	}
+	return fmt.Sprintf("Dashboard '%s', panel '%s' %s", d.Title, p.Title, message)
+}
 
+func (r *PanelRuleResults) AddError(d Dashboard, p Panel, message string) {
 	r.Results = append(r.Results, PanelResult{
 		Result: Result{
 			Severity: Error,
-			Message:  msg,
+			Message:  panelMessage(d, p, message),
 		},
 	})
 }
@@ -79,6 +159,30 @@ func dashboardMessage(d Dashboard, message string) string {
 	return fmt.Sprintf("Dashboard '%s' %s", d.Title, message)
 }
 
+// ruleMessage returns the part of message which comes from the rule, without the prefix locating the finding in
+// the dashboard of rc, such as "Dashboard 'Node', panel 'CPU' ". Unlike the prefix, it doesn't change when
+// dashboards or panels are renamed, or targets are reordered.
+func ruleMessage(rc ResultContext, message string) string {
+	if rc.Dashboard == nil {
+		return message
+	}
+	d := *rc.Dashboard
+	var prefix string
+	switch {
+	case rc.Target != nil && rc.Panel != nil:
+		prefix = targetMessage(d, *rc.Panel, *rc.Target, "")
+	case rc.Panel != nil:
+		prefix = panelMessage(d, *rc.Panel, "")
+	case rc.Template != nil:
+		prefix = templateMessage(d, *rc.Template, "")
+	case rc.Annotation != nil:
+		prefix = annotationMessage(d, *rc.Annotation, "")
+	default:
+		prefix = dashboardMessage(d, "")
+	}
+	return strings.TrimPrefix(message, prefix)
+}
+
 func (r *DashboardRuleResults) AddError(d Dashboard, message string) {
 	r.Results = append(r.Results, DashboardResult{
 		Result: Result{
@@ -109,36 +213,67 @@ func (r *DashboardRuleResults) AddWarning(d Dashboard, message string) {
 
 // ResultContext is used by ResultSet to keep all the state data about a lint execution and it's results.
 type ResultContext struct {
-	Result    RuleResults
-	Rule      Rule
-	Dashboard *Dashboard
-	Panel     *Panel
-	Target    *Target
+	Result     RuleResults
+	Rule       Rule
+	Dashboard  *Dashboard
+	Template   *Template
+	Annotation *Annotation
+	Panel      *Panel
+	Target     *Target
+	// File is the path of the dashboard file the result was found in, if known.
+	File string
+	// Location is the location of the dashboard, template, annotation, panel or target the result is about
+	// within File.
+	Location Location
+	// Reason is the reason given in the configuration for excluding the result, or downgrading it to a warning.
+	Reason string
+	// Owner and Ticket are the owner and ticket given in the configuration along with the reason.
+	Owner  string
+	Ticket string
+	// Suppression describes where in the dashboard the result was suppressed inline, if it was.
+	Suppression string
 }
 
 func (r Result) TtyPrint() {
-	var Reset = "\033[0m"
-	var Red = "\033[31m"
-	var Green = "\033[32m"
-	var Yellow = "\033[33m"
-	var Orange = "\033[38;5;208m"
-	var sym string
-	switch s := r.Severity; s {
-	case Success:
-		sym = Green + "✔️" + Reset
-	case Fixed:
-		sym = Orange + "🛠️ (fixed)" + Reset
-	case Exclude:
-		sym = "➖"
-	case Warning:
-		sym = Yellow + "⚠️" + Reset
-	case Error:
-		sym = Red + "❌" + Reset
-	case Quiet:
+	r.TtyFprint(os.Stdout)
+}
+
+// TtyFprint writes the result to w, with a coloured symbol for its severity.
+func (r Result) TtyFprint(w io.Writer) {
+	r.fprint(w, true)
+}
+
+const colorReset = "\033[0m"
+
+// severityColors are the ANSI escape codes of the colors of the severities.
+var severityColors = map[Severity]string{
+	Success: "\033[32m",
+	Fixed:   "\033[38;5;208m",
+	Info:    "\033[34m",
+	Warning: "\033[33m",
+	Error:   "\033[31m",
+}
+
+// severitySymbols are the symbols the severities are written with.
+var severitySymbols = map[Severity]string{
+	Success: "✔️",
+	Fixed:   "🛠️ (fixed)",
+	Exclude: "➖",
+	Info:    "ℹ️",
+	Warning: "⚠️",
+	Error:   "❌",
+}
+
+// fprint writes the result to w, with a symbol for its severity, colored if color is true.
+func (r Result) fprint(w io.Writer, color bool) {
+	sym, ok := severitySymbols[r.Severity]
+	if !ok {
 		return
 	}
-
-	fmt.Fprintf(os.Stdout, "[%s] %s\n", sym, r.Message)
+	if c, ok := severityColors[r.Severity]; ok && color {
+		sym = c + sym + colorReset
+	}
+	fmt.Fprintf(w, "[%s] %s\n", sym, r.Message)
 }
 
 type ResultSet struct {
@@ -162,6 +297,18 @@ func (rs *ResultSet) AddResult(r ResultContext) {
 	rs.results = append(rs.results, r)
 }
 
+// Merge appends all results of other to the ResultSet, recording file as the path they were linted from.
+// The configuration of other is adopted if the ResultSet has none of its own yet.
+func (rs *ResultSet) Merge(file string, other *ResultSet) {
+	for _, r := range other.results {
+		r.File = file
+		rs.results = append(rs.results, r)
+	}
+	if rs.config == nil {
+		rs.config = other.config
+	}
+}
+
 func (rs *ResultSet) MaximumSeverity() Severity {
 	retVal := Success
 	for _, res := range rs.results {
@@ -174,6 +321,19 @@ func (rs *ResultSet) MaximumSeverity() Severity {
 	return retVal
 }
 
+// Count returns the number of results with severity s.
+func (rs *ResultSet) Count(s Severity) int {
+	n := 0
+	for _, res := range rs.results {
+		for _, r := range res.Result.Results {
+			if r.Severity == s {
+				n++
+			}
+		}
+	}
+	return n
+}
+
 func (rs *ResultSet) ByRule() map[string][]ResultContext {
 	ret := make(map[string][]ResultContext)
 	for _, res := range rs.results {
@@ -187,30 +347,61 @@ func (rs *ResultSet) ByRule() map[string][]ResultContext {
 	return ret
 }
 
-func (rs *ResultSet) ReportByRule() {
-	byRule := rs.ByRule()
+// sortedRules returns the names of all rules with results, in alphabetical order.
+func sortedRules(byRule map[string][]ResultContext) []string {
 	rules := make([]string, 0, len(byRule))
 	for r := range byRule {
 		rules = append(rules, r)
 	}
 	sort.Strings(rules)
+	return rules
+}
 
-	for _, rule := range rules {
-		fmt.Fprintln(os.Stdout, byRule[rule][0].Rule.Description())
-		for _, rr := range byRule[rule] {
-			for _, r := range rr.Result.Results {
-				if r.Severity == Exclude && !rs.config.Verbose {
-					continue
-				}
-				r.TtyPrint()
-			}
+// isReported returns true if a result with the given severity should be part of a report.
+func (rs *ResultSet) isReported(s Severity) bool {
+	switch s {
+	case Quiet:
+		return false
+	case Exclude:
+		return rs.config != nil && rs.config.Verbose
+	}
+	return true
+}
+
+func (rs *ResultSet) ReportByRule() {
+	rs.ReportByRuleTo(os.Stdout)
+}
+
+// ReportByRuleTo writes all reported results to w, grouped by rule, with colored symbols for their severities.
+func (rs *ResultSet) ReportByRuleTo(w io.Writer) {
+	_ = TextReporter{Color: true}.Report(w, rs)
+}
+
+// fprintConfiguration writes the reason, owner and ticket given in the configuration for the result to w.
+func (rc ResultContext) fprintConfiguration(w io.Writer) {
+	var attrs []string
+	for _, attr := range []struct{ name, value string }{
+		{"reason", rc.Reason},
+		{"owner", rc.Owner},
+		{"ticket", rc.Ticket},
+	} {
+		if attr.value != "" {
+			attrs = append(attrs, fmt.Sprintf("%s: %s", attr.name, attr.value))
 		}
 	}
+	if len(attrs) > 0 {
+		fmt.Fprintf(w, "    %s\n", strings.Join(attrs, ", "))
+	}
 }
 
+// AutoFix fixes the problems of all results which can be fixed, other than the ones of rules disabled inline in
+// the dashboard, and returns how many it fixed.
 func (rs *ResultSet) AutoFix(d *Dashboard) int {
 	changes := 0
 	for _, r := range rs.results {
+		if _, ok := suppressionOf(r); ok {
+			continue
+		}
 		for i, fixableResult := range r.Result.Results {
 			if fixableResult.Fix != nil {
 				// Fix is only present when something can be fixed
@@ -0,0 +1,185 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+)
+
+// CustomRule is a rule declared in the configuration. It selects values from every dashboard, template, panel or
+// target, depending on its scope, and reports a problem if they don't satisfy its assertion.
+type CustomRule struct {
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	// Scope is what the rule checks, one of dashboard, template, panel or target.
+	Scope string `json:"scope" yaml:"scope"`
+	// Selector is a JSONPath selecting values from the JSON of what the rule checks, e.g. $.tags[*].
+	Selector string `json:"selector" yaml:"selector"`
+	// Pattern is a regular expression the selected values are matched against, if set.
+	Pattern string `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+	// Assert is what is expected of the selected values matching the pattern: exists, the default, requires at
+	// least one, absent requires none, and all requires all selected values to match.
+	Assert string `json:"assert,omitempty" yaml:"assert,omitempty"`
+	// Message is reported when the assertion fails, after the dashboard, template, panel or target it is about.
+	Message string `json:"message" yaml:"message"`
+	// Severity is the severity of the problems reported, one of error, the default, warning or info.
+	Severity string `json:"severity,omitempty" yaml:"severity,omitempty"`
+	// Datasources are the types of datasource the rule applies to, or empty if it applies to all of them.
+	Datasources []string `json:"datasources,omitempty" yaml:"datasources,omitempty"`
+}
+
+// customRuleScopes are the categories of rules of the scopes of custom rules.
+var customRuleScopes = map[string]string{
+	"dashboard": CategoryDashboard,
+	"template":  CategoryTemplate,
+	"panel":     CategoryPanel,
+	"target":    CategoryTarget,
+}
+
+// customRuleSeverities are the severities which can be set on custom rules.
+var customRuleSeverities = map[string]Severity{
+	"":        Error,
+	"error":   Error,
+	"warning": Warning,
+	"info":    Info,
+}
+
+// customRuleCheck is a compiled custom rule, checking the JSON of dashboards, templates, panels or targets.
+type customRuleCheck struct {
+	selector jsonPath
+	pattern  *regexp.Regexp
+	assert   string
+	severity Severity
+}
+
+// NewCustomRule returns the rule declared by cr, or an error if cr is invalid.
+func NewCustomRule(cr CustomRule) (Rule, error) {
+	if cr.Name == "" {
+		return nil, fmt.Errorf("no name set")
+	}
+	category, ok := customRuleScopes[cr.Scope]
+	if !ok {
+		return nil, fmt.Errorf("invalid scope '%s', must be one of dashboard, template, panel, target", cr.Scope)
+	}
+	c := customRuleCheck{assert: cr.Assert}
+	var err error
+	if c.selector, err = compileJSONPath(cr.Selector); err != nil {
+		return nil, fmt.Errorf("invalid selector '%s': %w", cr.Selector, err)
+	}
+	if cr.Pattern != "" {
+		if c.pattern, err = regexp.Compile(cr.Pattern); err != nil {
+			return nil, fmt.Errorf("invalid pattern '%s': %w", cr.Pattern, err)
+		}
+	}
+	switch cr.Assert {
+	case "":
+		c.assert = "exists"
+	case "exists", "absent":
+	case "all":
+		if c.pattern == nil {
+			return nil, fmt.Errorf("no pattern set to assert all values match")
+		}
+	default:
+		return nil, fmt.Errorf("invalid assert '%s', must be one of exists, absent, all", cr.Assert)
+	}
+	if c.severity, ok = customRuleSeverities[cr.Severity]; !ok {
+		return nil, fmt.Errorf("invalid severity '%s', must be one of error, warning, info", cr.Severity)
+	}
+	if cr.Message == "" {
+		return nil, fmt.Errorf("no message set")
+	}
+	if err := validateDatasources(cr.Datasources); err != nil {
+		return nil, err
+	}
+
+	description := cr.Description
+	if description == "" {
+		description = fmt.Sprintf("Checks the custom rule '%s' of the configuration.", cr.Name)
+	}
+	metadata := RuleMetadata{Category: category, Severity: c.severity, Datasources: cr.Datasources}
+	result := func(message string) Result {
+		return Result{Severity: c.severity, Message: message}
+	}
+
+	switch cr.Scope {
+	case "dashboard":
+		return &DashboardRuleFunc{name: cr.Name, description: description, metadata: metadata,
+			fn: func(d Dashboard) DashboardRuleResults {
+				r := DashboardRuleResults{}
+				if !c.check(d, d.Location, d) {
+					r.Results = append(r.Results, DashboardResult{Result: result(dashboardMessage(d, cr.Message))})
+				}
+				return r
+			},
+		}, nil
+	case "template":
+		return &TemplateRuleFunc{name: cr.Name, description: description, metadata: metadata,
+			fn: func(d Dashboard, t Template) TemplateRuleResults {
+				r := TemplateRuleResults{}
+				if !c.check(d, t.Location, t) {
+					r.Results = append(r.Results, TemplateResult{Result: result(templateMessage(d, t, cr.Message))})
+				}
+				return r
+			},
+		}, nil
+	case "panel":
+		return &PanelRuleFunc{name: cr.Name, description: description, metadata: metadata,
+			fn: func(d Dashboard, p Panel) PanelRuleResults {
+				r := PanelRuleResults{}
+				if !c.check(d, p.Location, p) {
+					r.AddError(d, p, cr.Message)
+					r.Results[0].Severity = c.severity
+				}
+				return r
+			},
+		}, nil
+	}
+	return &TargetRuleFunc{name: cr.Name, description: description, metadata: metadata,
+		fn: func(d Dashboard, p Panel, t Target) TargetRuleResults {
+			r := TargetRuleResults{}
+			if !c.check(d, t.Location, t) {
+				r.AddError(d, p, t, cr.Message)
+				r.Results[0].Severity = c.severity
+			}
+			return r
+		},
+	}, nil
+}
+
+// check returns true if the values selected from the JSON of the element at loc in d, or of v if d wasn't parsed
+// from JSON, satisfy the assertion.
+func (c customRuleCheck) check(d Dashboard, loc Location, v interface{}) bool {
+	element, err := d.element(loc.Pointer, v)
+	if err != nil {
+		return false
+	}
+	selected := c.selector.eval(element)
+	matching := 0
+	for _, value := range selected {
+		if c.pattern == nil || c.pattern.MatchString(jsonPathString(value)) {
+			matching++
+		}
+	}
+	switch c.assert {
+	case "absent":
+		return matching == 0
+	case "all":
+		return matching == len(selected)
+	}
+	return matching > 0
+}
+
+// element returns the JSON of the dashboard, template, panel or target at pointer in the source of d, decoded like
+// decodeTree does, or v marshalled if d wasn't parsed from JSON.
+func (d Dashboard) element(pointer string, v interface{}) (interface{}, error) {
+	if d.source != nil {
+		if s, ok := d.source.spans[pointer]; ok {
+			return decodeTree(d.source.buf[s.Start:s.End])
+		}
+	}
+	buf, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return decodeTree(buf)
+}
@@ -0,0 +1,162 @@
+package lint
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCustomRule(t *testing.T) {
+	dashboard, err := NewDashboard([]byte(`{
+		"title": "dash1",
+		"tags": ["team:a", "prod"],
+		"templating": {"list": [
+			{"name": "datasource", "type": "datasource", "query": "prometheus"},
+			{"name": "cluster", "type": "query", "datasource": "$datasource", "query": "label_values(up, cluster)"}
+		]},
+		"panels": [
+			{"id": 1, "title": "panel1", "type": "timeseries", "targets": [
+				{"refId": "A", "expr": "sum(rate(http_requests_total{cluster=\"$cluster\"}[5m]))"},
+				{"refId": "B", "expr": "sum(legacy_requests_total)"}
+			]},
+			{"id": 2, "title": "panel2", "type": "graph"}
+		]
+	}`))
+	require.NoError(t, err)
+
+	lint := func(t *testing.T, cr CustomRule) []Result {
+		rule, err := NewCustomRule(cr)
+		require.NoError(t, err)
+		rs := ResultSet{}
+		rule.Lint(dashboard, &rs)
+		var results []Result
+		for _, rc := range rs.results {
+			for _, r := range rc.Result.Results {
+				if r.Severity != Success {
+					results = append(results, r.Result)
+				}
+			}
+		}
+		return results
+	}
+
+	for _, tc := range []struct {
+		desc     string
+		rule     CustomRule
+		expected []Result
+	}{
+		{
+			desc:     "Should pass dashboards with a matching value",
+			rule:     CustomRule{Scope: "dashboard", Selector: "$.tags[*]", Pattern: "^team:"},
+			expected: nil,
+		},
+		{
+			desc: "Should report dashboards without a matching value",
+			rule: CustomRule{Scope: "dashboard", Selector: "$.tags[*]", Pattern: "^owner:", Message: "has no owner tag"},
+			expected: []Result{
+				{Severity: Error, Message: "Dashboard 'dash1' has no owner tag"},
+			},
+		},
+		{
+			desc: "Should report templates with a value which should be absent",
+			rule: CustomRule{Scope: "template", Selector: "$.query", Pattern: "^label_values", Assert: "absent", Message: "uses label_values"},
+			expected: []Result{
+				{Severity: Error, Message: "Dashboard 'dash1' template 'cluster' uses label_values"},
+			},
+		},
+		{
+			desc: "Should report panels with values which don't all match",
+			rule: CustomRule{Scope: "panel", Selector: "$.type", Pattern: "^timeseries$", Assert: "all", Severity: "warning", Message: "should be a time series"},
+			expected: []Result{
+				{Severity: Warning, Message: "Dashboard 'dash1', panel 'panel2' should be a time series"},
+			},
+		},
+		{
+			desc: "Should report targets without the selected value",
+			rule: CustomRule{Scope: "target", Selector: "$.expr", Pattern: `cluster="\$cluster"`, Severity: "info", Message: "should filter by cluster"},
+			expected: []Result{
+				{Severity: Info, Message: "Dashboard 'dash1', panel 'panel1', target idx '1' should filter by cluster"},
+			},
+		},
+		{
+			desc:     "Should check that selected values exist without a pattern",
+			rule:     CustomRule{Scope: "panel", Selector: "$.targets", Assert: "exists", Message: "has no targets"},
+			expected: []Result{{Severity: Error, Message: "Dashboard 'dash1', panel 'panel2' has no targets"}},
+		},
+		{
+			desc:     "Should only apply to dashboards of its datasources",
+			rule:     CustomRule{Scope: "dashboard", Selector: "$.tags[*]", Pattern: "^owner:", Datasources: []string{"loki"}},
+			expected: nil,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			tc.rule.Name = "custom-rule"
+			if tc.rule.Message == "" {
+				tc.rule.Message = "fails"
+			}
+			require.Equal(t, tc.expected, lint(t, tc.rule))
+		})
+	}
+
+	t.Run("Should check dashboards not parsed from JSON", func(t *testing.T) {
+		rule, err := NewCustomRule(CustomRule{Name: "custom-rule", Scope: "dashboard", Selector: "$.title", Pattern: "^dash", Message: "fails"})
+		require.NoError(t, err)
+		rs := ResultSet{}
+		rule.Lint(Dashboard{Title: "other"}, &rs)
+		require.Equal(t, Error, rs.MaximumSeverity())
+	})
+
+	for _, tc := range []struct {
+		rule     CustomRule
+		expected string
+	}{
+		{CustomRule{Scope: "dashboard", Selector: "$", Message: "m"}, "no name set"},
+		{CustomRule{Name: "r", Scope: "row", Selector: "$", Message: "m"}, "invalid scope 'row', must be one of dashboard, template, panel, target"},
+		{CustomRule{Name: "r", Scope: "panel", Selector: "title", Message: "m"}, "invalid selector 'title': must start with $"},
+		{CustomRule{Name: "r", Scope: "panel", Selector: "$", Pattern: "(", Message: "m"}, "invalid pattern '(': error parsing regexp: missing closing ): `(`"},
+		{CustomRule{Name: "r", Scope: "panel", Selector: "$", Assert: "all", Message: "m"}, "no pattern set to assert all values match"},
+		{CustomRule{Name: "r", Scope: "panel", Selector: "$", Assert: "none", Message: "m"}, "invalid assert 'none', must be one of exists, absent, all"},
+		{CustomRule{Name: "r", Scope: "panel", Selector: "$", Severity: "fatal", Message: "m"}, "invalid severity 'fatal', must be one of error, warning, info"},
+		{CustomRule{Name: "r", Scope: "panel", Selector: "$"}, "no message set"},
+	} {
+		_, err := NewCustomRule(tc.rule)
+		require.EqualError(t, err, tc.expected)
+	}
+}
+
+func TestConfigurationCustomRules(t *testing.T) {
+	dir := t.TempDir()
+	writeConfig(t, filepath.Join(dir, "base.lint"), `
+custom_rules:
+  - name: dashboard-team-tag-rule
+    scope: dashboard
+    selector: $.tags[*]
+    pattern: '^team:'
+    message: should have a team tag
+`)
+	writeConfig(t, filepath.Join(dir, ".lint"), `
+extends: [base.lint]
+exclusions:
+  dashboard-team-tag-rule:
+    reason: Owned by everyone
+`)
+	config := NewConfigurationFile()
+	require.NoError(t, config.Load(filepath.Join(dir, ".lint")), "should know custom rules of extended configurations")
+
+	rules, err := config.AllRules()
+	require.NoError(t, err)
+	var names []string
+	for _, r := range rules.Rules() {
+		names = append(names, r.Name())
+	}
+	require.Contains(t, names, "dashboard-team-tag-rule")
+
+	rs, err := rules.Lint([]Dashboard{{Title: "dash1"}})
+	require.NoError(t, err)
+	rs.Configure(config)
+	results := rs.ByRule()["dashboard-team-tag-rule"]
+	require.Len(t, results, 1)
+	require.Equal(t, Exclude, results[0].Result.Results[0].Severity)
+	require.Empty(t, config.Unused())
+}
@@ -8,6 +8,12 @@ func NewPanelDatasourceRule() *PanelRuleFunc {
 	return &PanelRuleFunc{
 		name:        "panel-datasource-rule",
 		description: "Checks that each panel uses the templated datasource.",
+		metadata: RuleMetadata{
+			Category: CategoryPanel,
+			Severity: Error,
+			DocsURL:  rulesDocsURL + "panel-datasource-rule.md",
+			Since:    firstRelease,
+		},
 		fn: func(d Dashboard, p Panel) PanelRuleResults {
 			r := PanelRuleResults{}
 
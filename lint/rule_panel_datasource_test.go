@@ -104,7 +104,30 @@ func testRuleWithAutofix(t *testing.T, rule Rule, d *Dashboard, result []Result,
 		rs.AutoFix(d)
 	}
 	require.Len(t, rs.results, 1)
-	actual := rs.results[0].Result
+	requireResults(t, rs.results[0], result)
+}
+
+// testTemplateRuleWithAutofix is like testRuleWithAutofix for template rules, expecting a single result for
+// the template with the given name.
+func testTemplateRuleWithAutofix(t *testing.T, rule Rule, d *Dashboard, template string, result []Result, autofix bool) {
+	rs := ResultSet{}
+	rule.Lint(*d, &rs)
+	if autofix {
+		rs.AutoFix(d)
+	}
+	var results []ResultContext
+	for _, r := range rs.results {
+		if r.Template != nil && r.Template.Name == template {
+			results = append(results, r)
+		}
+	}
+	require.Len(t, results, 1)
+	requireResults(t, results[0], result)
+}
+
+func requireResults(t *testing.T, rc ResultContext, result []Result) {
+	t.Helper()
+	actual := rc.Result
 	if actual.Results[0].Severity == Quiet {
 		// all test cases expect success
 		actual.Results[0].Severity = Success
@@ -4,6 +4,11 @@ func NewPanelNoTargetsRule() *PanelRuleFunc {
 	return &PanelRuleFunc{
 		name:        "panel-no-targets-rule",
 		description: "Checks that each panel has at least one target.",
+		metadata: RuleMetadata{
+			Category: CategoryPanel,
+			Severity: Error,
+			Since:    firstRelease,
+		},
 		fn: func(d Dashboard, p Panel) PanelRuleResults {
 			r := PanelRuleResults{}
 			switch p.Type {
@@ -6,6 +6,12 @@ func NewPanelTitleDescriptionRule() *PanelRuleFunc {
 	return &PanelRuleFunc{
 		name:        "panel-title-description-rule",
 		description: "Checks that each panel has a title and description.",
+		metadata: RuleMetadata{
+			Category: CategoryPanel,
+			Severity: Error,
+			DocsURL:  rulesDocsURL + "panel-title-description-rule.md",
+			Since:    firstRelease,
+		},
 		fn: func(d Dashboard, p Panel) PanelRuleResults {
 			r := PanelRuleResults{}
 			switch p.Type {
@@ -66,6 +66,12 @@ func NewPanelUnitsRule() *PanelRuleFunc {
 	return &PanelRuleFunc{
 		name:        "panel-units-rule",
 		description: "Checks that each panel uses has valid units defined.",
+		metadata: RuleMetadata{
+			Category: CategoryPanel,
+			Severity: Error,
+			DocsURL:  rulesDocsURL + "panel-units-rule.md",
+			Since:    firstRelease,
+		},
 		fn: func(d Dashboard, p Panel) PanelRuleResults {
 			r := PanelRuleResults{}
 			switch p.Type {
@@ -11,9 +11,15 @@ func NewTargetCounterAggRule() *TargetRuleFunc {
 	return &TargetRuleFunc{
 		name:        "target-counter-agg-rule",
 		description: "Checks that any counter metric (ending in _total) is aggregated with rate, irate, or increase.",
+		metadata: RuleMetadata{
+			Category:    CategoryTarget,
+			Severity:    Error,
+			Datasources: []string{Prometheus},
+			Since:       firstRelease,
+		},
 		fn: func(d Dashboard, p Panel, t Target) TargetRuleResults {
 			r := TargetRuleResults{}
-			expr, err := parsePromQL(t.Expr, d.Templating.List)
+			expr, err := d.parsePromQL(t.Expr)
 			if err != nil {
 				// Invalid PromQL is another rule
 				return r
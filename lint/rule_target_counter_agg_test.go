@@ -92,8 +92,15 @@ func TestTargetCounterAggRule(t *testing.T) {
 		dashboard := Dashboard{
 			Title: "dashboard",
 			Templating: struct {
-				List []Template "json:\"list\""
-			}{List: []Template{}},
+				List []Template `json:"list"`
+			}{
+				List: []Template{
+					{
+						Type:  "datasource",
+						Query: "prometheus",
+					},
+				},
+			},
 			Panels: []Panel{tc.panel},
 		}
 
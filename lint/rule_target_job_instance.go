@@ -2,34 +2,141 @@ package lint
 
 import (
 	"fmt"
+	"regexp"
+	"slices"
+	"strings"
 
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/promql/parser"
 )
 
-func newTargetRequiredMatcherRule(matcher string) *TargetRuleFunc {
+// RequiredMatcher is a label matcher every selector of the queries of dashboards must have, such as
+// cluster=~"$cluster". It is checked by a target-<label>-rule and, if its value is a variable, a
+// template-<variable>-rule checking that the dashboard has a template for it.
+type RequiredMatcher struct {
+	Label string `json:"label" yaml:"label"`
+	// Operators are the match types allowed, out of =, !=, =~ and !~. It defaults to =~.
+	Operators []string `json:"operators,omitempty" yaml:"operators,omitempty"`
+	// Value is the value expected, $<label> by default. Variables can be written as $name or ${name}.
+	Value string `json:"value,omitempty" yaml:"value,omitempty"`
+	// Datasources are the types of datasource whose queries must have the matcher, out of prometheus, for PromQL
+	// selectors, and loki, for LogQL stream selectors. It defaults to prometheus.
+	Datasources []string `json:"datasources,omitempty" yaml:"datasources,omitempty"`
+}
+
+// defaultRequiredMatchers are the labels of the required matchers which are built-in rules.
+var defaultRequiredMatchers = []string{"job", "instance"}
+
+var matchTypes = map[string]labels.MatchType{
+	"=":  labels.MatchEqual,
+	"!=": labels.MatchNotEqual,
+	"=~": labels.MatchRegexp,
+	"!~": labels.MatchNotRegexp,
+}
+
+var labelNameRegexp = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// requiredMatcher is a RequiredMatcher with its defaults set.
+type requiredMatcher struct {
+	label       string
+	types       []labels.MatchType
+	value       string
+	variable    string
+	datasources []string
+}
+
+func (m RequiredMatcher) compile() (requiredMatcher, error) {
+	if m.Label == "" {
+		return requiredMatcher{}, fmt.Errorf("no label set")
+	}
+	if !labelNameRegexp.MatchString(m.Label) {
+		return requiredMatcher{}, fmt.Errorf("invalid label name '%s'", m.Label)
+	}
+	c := requiredMatcher{label: m.Label, value: m.Value, datasources: m.Datasources}
+	for _, op := range m.Operators {
+		ty, ok := matchTypes[op]
+		if !ok {
+			return requiredMatcher{}, fmt.Errorf("invalid operator '%s', must be one of =, !=, =~, !~", op)
+		}
+		c.types = append(c.types, ty)
+	}
+	if len(c.types) == 0 {
+		c.types = []labels.MatchType{labels.MatchRegexp}
+	}
+	if c.value == "" {
+		c.value = "$" + m.Label
+	}
+	c.variable = variableName(c.value)
+	if err := validateDatasources(m.Datasources); err != nil {
+		return requiredMatcher{}, err
+	}
+	if len(c.datasources) == 0 {
+		c.datasources = []string{Prometheus}
+	}
+	return c, nil
+}
+
+// variableName returns the name of the variable value is, written as $name or ${name}, or an empty string if
+// value isn't a single variable. The format of ${name:format} is left out.
+func variableName(value string) string {
+	m := variableRegexp.FindStringSubmatch(value)
+	if m == nil || m[0] != value || m[3] != "" {
+		return ""
+	}
+	name, _, _ := strings.Cut(m[1]+m[2], ":")
+	return name
+}
+
+// NewRequiredMatcherRules returns the rules checking m: a target-<label>-rule, and a template-<variable>-rule if
+// the value of m is a variable. The template has to be a multi select if m allows regular expression matchers.
+func NewRequiredMatcherRules(m RequiredMatcher) ([]Rule, error) {
+	c, err := m.compile()
+	if err != nil {
+		return nil, err
+	}
+	rules := []Rule{newTargetRequiredMatcherRule(c)}
+	if c.variable != "" {
+		multi := slices.ContainsFunc(c.types, func(ty labels.MatchType) bool {
+			return ty == labels.MatchRegexp || ty == labels.MatchNotRegexp
+		})
+		rules = append(rules, newTemplateRequiredRule(c.variable, c.datasources, multi))
+	}
+	return rules, nil
+}
+
+func newTargetRequiredMatcherRule(m requiredMatcher) *TargetRuleFunc {
+	var queries []string
+	if slices.Contains(m.datasources, Prometheus) {
+		queries = append(queries, "PromQL")
+	}
+	if slices.Contains(m.datasources, Loki) {
+		queries = append(queries, "LogQL")
+	}
+	metadata := RuleMetadata{
+		Category:    CategoryTarget,
+		Severity:    Error,
+		Datasources: m.datasources,
+	}
+	if slices.Contains(defaultRequiredMatchers, m.label) {
+		metadata.DocsURL = rulesDocsURL + fmt.Sprintf("target-%s-rule.md", m.label)
+		metadata.Since = firstRelease
+	}
 	return &TargetRuleFunc{
-		name:        fmt.Sprintf("target-%s-rule", matcher),
-		description: fmt.Sprintf("Checks that every PromQL query has a %s matcher.", matcher),
+		name:        fmt.Sprintf("target-%s-rule", m.label),
+		description: fmt.Sprintf("Checks that every %s query has a %s matcher.", strings.Join(queries, " and "), m.label),
+		metadata:    metadata,
 		fn: func(d Dashboard, p Panel, t Target) TargetRuleResults {
 			r := TargetRuleResults{}
-			// TODO: The RuleSet should be responsible for routing rule checks based on their query type (prometheus, loki, mysql, etc)
-			// and for ensuring that the datasource is set.
-			if t := getTemplateDatasource(d); t == nil || t.Query != Prometheus {
-				// Missing template datasource is a separate rule.
-				// Non prometheus datasources don't have rules yet
-				return r
-			}
-
-			node, err := parsePromQL(t.Expr, d.Templating.List)
+			language, selectors, err := targetSelectors(d, t, m.datasources)
 			if err != nil {
-				// Invalid PromQL is another rule
+				// Invalid queries are another rule
 				return r
 			}
 
-			for _, selector := range parser.ExtractSelectors(node) {
-				if err := checkForMatcher(selector, matcher, labels.MatchRegexp, fmt.Sprintf("$%s", matcher)); err != nil {
-					r.AddError(d, p, t, fmt.Sprintf("invalid PromQL query '%s': %v", t.Expr, err))
+			for _, selector := range selectors {
+				if err := checkForMatcher(selector, m); err != nil {
+					r.AddError(d, p, t, fmt.Sprintf("invalid %s query '%s': %v", language, t.Expr, err))
 				}
 			}
 
@@ -38,10 +145,54 @@ func newTargetRequiredMatcherRule(matcher string) *TargetRuleFunc {
 	}
 }
 
+// targetSelectors returns the query language of t, and the label matchers of all selectors of its query: PromQL
+// selectors, or LogQL stream selectors for targets of Loki. No selectors are returned if the datasource of t isn't
+// one of datasources.
+func targetSelectors(d Dashboard, t Target, datasources []string) (string, [][]*labels.Matcher, error) {
+	if targetDatasourceType(d, t) == Loki {
+		if !slices.Contains(datasources, Loki) {
+			return "LogQL", nil, nil
+		}
+		expr, err := d.parseLogQL(t.Expr)
+		if err != nil {
+			return "LogQL", nil, err
+		}
+		var selectors [][]*labels.Matcher
+		expr.Walk(func(e syntax.Expr) {
+			if m, ok := e.(*syntax.MatchersExpr); ok {
+				selectors = append(selectors, m.Matchers())
+			}
+		})
+		return "LogQL", selectors, nil
+	}
+	if !slices.Contains(datasources, Prometheus) {
+		return "PromQL", nil, nil
+	}
+	node, err := d.parsePromQL(t.Expr)
+	if err != nil {
+		return "PromQL", nil, err
+	}
+	return "PromQL", parser.ExtractSelectors(node), nil
+}
+
+// targetDatasourceType returns the type of the datasource of t, or of the templated datasource of d if t doesn't
+// set one.
+func targetDatasourceType(d Dashboard, t Target) string {
+	if ds, err := t.GetDataSource(); err == nil && ds.Type != "" {
+		return ds.Type
+	}
+	if templateDS := getTemplateDatasource(d); templateDS != nil {
+		return templateDS.Query
+	}
+	return ""
+}
+
 func NewTargetJobRule() *TargetRuleFunc {
-	return newTargetRequiredMatcherRule("job")
+	m, _ := RequiredMatcher{Label: "job"}.compile()
+	return newTargetRequiredMatcherRule(m)
 }
 
 func NewTargetInstanceRule() *TargetRuleFunc {
-	return newTargetRequiredMatcherRule("instance")
+	m, _ := RequiredMatcher{Label: "instance"}.compile()
+	return newTargetRequiredMatcherRule(m)
 }
@@ -3,6 +3,8 @@ package lint
 import (
 	"fmt"
 	"testing"
+
+	"github.com/stretchr/testify/require"
 )
 
 func testTargetRequiredMatcherRule(t *testing.T, matcher string) {
@@ -96,3 +98,156 @@ func TestTargetJobInstanceRule(t *testing.T) {
 	testTargetRequiredMatcherRule(t, "job")
 	testTargetRequiredMatcherRule(t, "instance")
 }
+
+func TestTargetRequiredMatcherRule(t *testing.T) {
+	rules, err := NewRequiredMatcherRules(RequiredMatcher{
+		Label:       "cluster",
+		Operators:   []string{"=~", "="},
+		Datasources: []string{Prometheus, Loki},
+	})
+	require.NoError(t, err)
+	require.Len(t, rules, 2)
+	require.Equal(t, "target-cluster-rule", rules[0].Name())
+	require.Equal(t, "Checks that every PromQL and LogQL query has a cluster matcher.", rules[0].Description())
+	require.Equal(t, "template-cluster-rule", rules[1].Name())
+
+	for _, tc := range []struct {
+		desc       string
+		datasource string
+		expr       string
+		result     Result
+	}{
+		{
+			desc:       "PromQL selectors with an allowed operator",
+			datasource: Prometheus,
+			expr:       `sum(rate(foo{cluster="$cluster"}[5m])) / sum(rate(bar{cluster=~"${cluster}"}[5m]))`,
+			result:     ResultSuccess,
+		},
+		{
+			desc:       "PromQL selectors with another operator",
+			datasource: Prometheus,
+			expr:       `sum(rate(foo{cluster!="$cluster"}[5m]))`,
+			result: Result{
+				Severity: Error,
+				Message:  `Dashboard 'dashboard', panel 'panel', target idx '0' invalid PromQL query 'sum(rate(foo{cluster!="$cluster"}[5m]))': cluster selector is !=, not =~ or =`,
+			},
+		},
+		{
+			desc:       "LogQL stream selectors with the matcher",
+			datasource: Loki,
+			expr:       `sum(count_over_time({cluster=~"$cluster", app="foo"} |= "error" [$__auto]))`,
+			result:     ResultSuccess,
+		},
+		{
+			desc:       "LogQL stream selectors without the matcher",
+			datasource: Loki,
+			expr:       `{app="foo"} |= "error"`,
+			result: Result{
+				Severity: Error,
+				Message:  `Dashboard 'dashboard', panel 'panel', target idx '0' invalid LogQL query '{app="foo"} |= "error"': cluster selector not found`,
+			},
+		},
+		{
+			desc:       "Invalid LogQL",
+			datasource: Loki,
+			expr:       `{app="foo"`,
+			result:     ResultSuccess,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			dashboard := Dashboard{
+				Title: "dashboard",
+				Templating: struct {
+					List []Template `json:"list"`
+				}{
+					List: []Template{{Type: "datasource", Query: tc.datasource}},
+				},
+				Panels: []Panel{{Title: "panel", Type: "timeseries", Targets: []Target{{Expr: tc.expr}}}},
+			}
+			testRule(t, rules[0], dashboard, tc.result)
+		})
+	}
+
+	t.Run("Should only check queries of its datasources", func(t *testing.T) {
+		rules, err := NewRequiredMatcherRules(RequiredMatcher{Label: "namespace", Value: "default", Operators: []string{"="}})
+		require.NoError(t, err)
+		require.Len(t, rules, 1, "should not require a template for values which aren't variables")
+		dashboard := Dashboard{
+			Title: "dashboard",
+			Templating: struct {
+				List []Template `json:"list"`
+			}{
+				List: []Template{{Type: "datasource", Query: Prometheus}},
+			},
+			Panels: []Panel{{Title: "panel", Type: "timeseries", Targets: []Target{
+				{Expr: `foo{namespace="default"}`},
+				{Expr: `{app="foo"}`, Datasource: map[string]interface{}{"type": Loki, "uid": "logs"}},
+				{Expr: `foo{namespace="kube-system"}`},
+			}}},
+		}
+		rs := ResultSet{}
+		rules[0].Lint(dashboard, &rs)
+		var messages []string
+		for _, rc := range rs.results {
+			for _, r := range rc.Result.Results {
+				if r.Severity != Success {
+					messages = append(messages, r.Message)
+				}
+			}
+		}
+		require.Equal(t, []string{
+			`Dashboard 'dashboard', panel 'panel', target idx '2' invalid PromQL query 'foo{namespace="kube-system"}': namespace selector is kube-system, not default`,
+		}, messages)
+	})
+
+	t.Run("Should check templates for the datasources and operators of the matcher", func(t *testing.T) {
+		rules, err := NewRequiredMatcherRules(RequiredMatcher{Label: "app", Operators: []string{"="}, Datasources: []string{Loki}})
+		require.NoError(t, err)
+		require.Len(t, rules, 2)
+
+		for _, tc := range []struct {
+			desc     string
+			template Template
+			result   []Result
+		}{
+			{
+				desc:     "Single select querying Loki",
+				template: Template{Name: "app", Label: "App", Type: "query", Datasource: "$loki_datasource"},
+				result:   []Result{ResultSuccess},
+			},
+			{
+				desc:     "Template querying Prometheus",
+				template: Template{Name: "app", Label: "App", Type: "custom", Datasource: "$prometheus_datasource"},
+				result: []Result{
+					{Severity: Error, Message: "Dashboard 'dashboard' app template should use datasource '$datasource', is currently '$prometheus_datasource'"},
+					{Severity: Error, Message: "Dashboard 'dashboard' app template should be a Loki query, is currently 'custom'"},
+				},
+			},
+		} {
+			t.Run(tc.desc, func(t *testing.T) {
+				dashboard := Dashboard{
+					Title: "dashboard",
+					Templating: struct {
+						List []Template `json:"list"`
+					}{
+						List: []Template{{Type: "datasource", Query: Loki}, tc.template},
+					},
+				}
+				testMultiResultRule(t, rules[1], dashboard, tc.result)
+			})
+		}
+	})
+
+	for _, tc := range []struct {
+		matcher  RequiredMatcher
+		expected string
+	}{
+		{RequiredMatcher{}, "no label set"},
+		{RequiredMatcher{Label: "k8s-cluster"}, "invalid label name 'k8s-cluster'"},
+		{RequiredMatcher{Label: "cluster", Operators: []string{"=="}}, "invalid operator '==', must be one of =, !=, =~, !~"},
+		{RequiredMatcher{Label: "cluster", Datasources: []string{"graphite"}}, "invalid datasource 'graphite', must be one of prometheus, loki"},
+	} {
+		_, err := NewRequiredMatcherRules(tc.matcher)
+		require.EqualError(t, err, tc.expected)
+	}
+}
@@ -8,6 +8,13 @@ func NewTargetLogQLRule() *TargetRuleFunc {
 	return &TargetRuleFunc{
 		name:        "target-logql-rule",
 		description: "Checks that each target uses a valid LogQL query.",
+		metadata: RuleMetadata{
+			Category:    CategoryTarget,
+			Severity:    Error,
+			Datasources: []string{Loki},
+			DocsURL:     rulesDocsURL + "target-logql-rule.md",
+			Since:       firstRelease,
+		},
 		fn: func(d Dashboard, p Panel, t Target) TargetRuleResults {
 			r := TargetRuleResults{}
 
@@ -16,19 +23,6 @@ func NewTargetLogQLRule() *TargetRuleFunc {
 				return r
 			}
 
-			// Check if the datasource is Loki
-			isLoki := false
-			if templateDS := getTemplateDatasource(d); templateDS != nil && templateDS.Query == Loki {
-				isLoki = true
-			} else if ds, err := t.GetDataSource(); err == nil && ds.Type == Loki {
-				isLoki = true
-			}
-
-			// skip if the datasource is not Loki
-			if !isLoki {
-				return r
-			}
-
 			if !panelHasQueries(p) {
 				return r
 			}
@@ -47,7 +41,7 @@ func NewTargetLogQLRule() *TargetRuleFunc {
 			}
 
 			// Parse the LogQL query
-			_, err := parseLogQL(t.Expr, d.Templating.List)
+			_, err := d.parseLogQL(t.Expr)
 			if err != nil {
 				r.AddError(d, p, t, fmt.Sprintf("invalid LogQL query '%s': %v", t.Expr, err))
 				return r
@@ -25,6 +25,13 @@ func NewTargetLogQLAutoRule() *TargetRuleFunc {
 	return &TargetRuleFunc{
 		name:        "target-logql-auto-rule",
 		description: "Checks that each Loki target uses $__auto for range vectors when appropriate.",
+		metadata: RuleMetadata{
+			Category:    CategoryTarget,
+			Severity:    Error,
+			Datasources: []string{Loki},
+			DocsURL:     rulesDocsURL + "target-logql-auto-rule.md",
+			Since:       firstRelease,
+		},
 		fn: func(d Dashboard, p Panel, t Target) TargetRuleResults {
 			r := TargetRuleResults{}
 
@@ -33,25 +40,12 @@ func NewTargetLogQLAutoRule() *TargetRuleFunc {
 				return r
 			}
 
-			// check if the datasource is Loki
-			isLoki := false
-			if templateDS := getTemplateDatasource(d); templateDS != nil && templateDS.Query == Loki {
-				isLoki = true
-			} else if ds, err := t.GetDataSource(); err == nil && ds.Type == Loki {
-				isLoki = true
-			}
-
-			// skip if the datasource is not Loki
-			if !isLoki {
-				return r
-			}
-
 			// skip if the panel does not have queries
 			if !panelHasQueries(p) {
 				return r
 			}
 
-			parsedExpr, err := parseLogQL(t.Expr, d.Templating.List)
+			parsedExpr, err := d.parseLogQL(t.Expr)
 			if err != nil {
 				r.AddError(d, p, t, fmt.Sprintf("Invalid LogQL query: %v", err))
 				return r
@@ -39,14 +39,16 @@ func NewTargetPromQLRule() *TargetRuleFunc {
 	return &TargetRuleFunc{
 		name:        "target-promql-rule",
 		description: "Checks that each target uses a valid PromQL query.",
+		metadata: RuleMetadata{
+			Category:    CategoryTarget,
+			Severity:    Error,
+			Datasources: []string{Prometheus},
+			DocsURL:     rulesDocsURL + "target-promql-rule.md",
+			Since:       firstRelease,
+		},
 		fn: func(d Dashboard, p Panel, t Target) TargetRuleResults {
 			r := TargetRuleResults{}
 
-			if t := getTemplateDatasource(d); t == nil || t.Query != Prometheus {
-				// Missing template datasources is a separate rule.
-				return r
-			}
-
 			if !panelHasQueries(p) {
 				return r
 			}
@@ -63,7 +65,7 @@ func NewTargetPromQLRule() *TargetRuleFunc {
 				}
 			}
 
-			if _, err := parsePromQL(t.Expr, d.Templating.List); err != nil {
+			if _, err := d.parsePromQL(t.Expr); err != nil {
 				r.AddError(d, p, t, fmt.Sprintf("invalid PromQL query '%s': %v", t.Expr, err))
 			}
 
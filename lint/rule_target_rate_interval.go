@@ -27,19 +27,21 @@ func NewTargetRateIntervalRule() *TargetRuleFunc {
 	return &TargetRuleFunc{
 		name:        "target-rate-interval-rule",
 		description: "Checks that each target uses $__rate_interval.",
+		metadata: RuleMetadata{
+			Category:    CategoryTarget,
+			Severity:    Error,
+			Datasources: []string{Prometheus},
+			DocsURL:     rulesDocsURL + "target-rate-interval-rule.md",
+			Since:       firstRelease,
+		},
 		fn: func(d Dashboard, p Panel, t Target) TargetRuleResults {
 			r := TargetRuleResults{}
-			if t := getTemplateDatasource(d); t == nil || t.Query != Prometheus {
-				// Missing template datasources is a separate rule.
-				return r
-			}
-
 			if !panelHasQueries(p) {
 				// Don't lint certain types of panels.
 				return r
 			}
 
-			expr, err := parsePromQL(t.Expr, d.Templating.List)
+			expr, err := d.parsePromQL(t.Expr)
 			if err != nil {
 				// Invalid PromQL is another rule
 				return r
@@ -12,6 +12,12 @@ func NewTemplateDatasourceRule() *DashboardRuleFunc {
 	return &DashboardRuleFunc{
 		name:        "template-datasource-rule",
 		description: "Checks that the dashboard has a templated datasource.",
+		metadata: RuleMetadata{
+			Category: CategoryTemplate,
+			Severity: Error,
+			DocsURL:  rulesDocsURL + "template-datasource-rule.md",
+			Since:    firstRelease,
+		},
 		fn: func(d Dashboard) DashboardRuleResults {
 			r := DashboardRuleResults{}
 
@@ -20,8 +26,8 @@ func NewTemplateDatasourceRule() *DashboardRuleFunc {
 				r.AddError(d, "does not have a templated data source")
 			}
 
-			// TODO: Should there be a "Template" rule type which will iterate over all dashboard templates and execute rules?
-			// This will only return one linting error at a time, when there may be multiple issues with templated datasources.
+			// This is a dashboard rule rather than a template rule, as it also checks that there is a templated
+			// datasource at all, and the names allowed depend on how many of them there are.
 
 			titleCaser := cases.Title(language.English)
 
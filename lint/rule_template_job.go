@@ -2,50 +2,75 @@ package lint
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 )
 
 func NewTemplateJobRule() *DashboardRuleFunc {
+	return newTemplateRequiredRule("job", []string{Prometheus}, true)
+}
+
+// newTemplateRequiredRule returns the rule checking that dashboards of datasources have a template of the variable
+// name querying one of them. If multi is true, as for regular expression matchers, the template must be a multi
+// select whose all value is .+, like the templates of jobs.
+func newTemplateRequiredRule(name string, datasources []string, multi bool) *DashboardRuleFunc {
+	metadata := RuleMetadata{
+		Category:    CategoryTemplate,
+		Severity:    Error,
+		Datasources: datasources,
+	}
+	if slices.Contains(defaultRequiredMatchers, name) {
+		metadata.DocsURL = rulesDocsURL + fmt.Sprintf("template-%s-rule.md", name)
+		metadata.Since = firstRelease
+	}
 	return &DashboardRuleFunc{
-		name:        "template-job-rule",
-		description: "Checks that the dashboard has a templated job.",
+		name:        fmt.Sprintf("template-%s-rule", name),
+		description: fmt.Sprintf("Checks that the dashboard has a templated %s.", name),
+		metadata:    metadata,
 		fn: func(d Dashboard) DashboardRuleResults {
 			r := DashboardRuleResults{}
 
-			template := getTemplateDatasource(d)
-			if template == nil || template.Query != Prometheus {
-				return r
-			}
-
-			checkTemplate(d, "job", &r)
+			checkTemplate(d, name, datasources, multi, &r)
 			return r
 		},
 	}
 }
 
-func checkTemplate(d Dashboard, name string, r *DashboardRuleResults) {
+// datasourceNames are the names of the types of datasource in messages.
+var datasourceNames = map[string]string{
+	Prometheus: "Prometheus",
+	Loki:       "Loki",
+}
+
+func checkTemplate(d Dashboard, name string, datasources []string, multi bool, r *DashboardRuleResults) {
 	t := getTemplate(d, name)
 	if t == nil {
 		r.AddError(d, fmt.Sprintf("is missing the %s template", name))
 		return
 	}
 
-	// TODO: Adding the prometheus_datasource here is hacky. This check function also assumes that all template vars which it will
-	// ever check are only prometheus queries, which may not always be the case.
 	src, err := t.GetDataSource()
 	if err != nil {
 		r.AddError(d, fmt.Sprintf("%s template has invalid datasource %v", name, err))
 	}
 
+	// Besides $datasource, dashboards of several types of datasource template one per type, e.g. $loki_datasource
+	variables := []string{"datasource"}
+	var queries []string
+	for _, ds := range datasources {
+		variables = append(variables, ds+"_datasource")
+		queries = append(queries, datasourceNames[ds])
+	}
 	srcUid := src.UID
-	if srcUid != "$datasource" && srcUid != "${datasource}" && srcUid != "$prometheus_datasource" && srcUid != "${prometheus_datasource}" {
+	if !slices.ContainsFunc(variables, func(v string) bool { return srcUid == "$"+v || srcUid == "${"+v+"}" }) {
 		r.AddError(d, fmt.Sprintf("%s template should use datasource '$datasource', is currently '%s'", name, srcUid))
 	}
 
 	if t.Type != targetTypeQuery {
-		r.AddError(d, fmt.Sprintf("%s template should be a Prometheus query, is currently '%s'", name, t.Type))
+		r.AddError(d, fmt.Sprintf("%s template should be a %s query, is currently '%s'", name, strings.Join(queries, " or "), t.Type))
 	}
 
 	titleCaser := cases.Title(language.English)
@@ -55,6 +80,11 @@ func checkTemplate(d Dashboard, name string, r *DashboardRuleResults) {
 		r.AddWarning(d, fmt.Sprintf("%s template should be a labeled '%s', is currently '%s'", name, labelTitle, t.Label))
 	}
 
+	// Several values, or all of them, can only be selected with regular expression matchers
+	if !multi {
+		return
+	}
+
 	if !t.Multi {
 		r.AddError(d, fmt.Sprintf("%s template should be a multi select", name))
 	}
@@ -39,26 +39,25 @@ func parseTemplatedLabelPromQL(t Template, variables []Template) error {
 	return err
 }
 
-func NewTemplateLabelPromQLRule() *DashboardRuleFunc {
-	return &DashboardRuleFunc{
+func NewTemplateLabelPromQLRule() *TemplateRuleFunc {
+	return &TemplateRuleFunc{
 		name:        "template-label-promql-rule",
 		description: "Checks that the dashboard templated labels have proper PromQL expressions.",
-		fn: func(d Dashboard) DashboardRuleResults {
-			r := DashboardRuleResults{}
-
-			template := getTemplateDatasource(d)
-			if template == nil || template.Query != Prometheus {
+		metadata: RuleMetadata{
+			Category:    CategoryTemplate,
+			Severity:    Error,
+			Datasources: []string{Prometheus},
+			DocsURL:     rulesDocsURL + "template-label-promql-rule.md",
+			Since:       firstRelease,
+		},
+		fn: func(d Dashboard, t Template) TemplateRuleResults {
+			r := TemplateRuleResults{}
+			if t.Type != targetTypeQuery {
 				return r
 			}
-			for _, template := range d.Templating.List {
-				if template.Type != targetTypeQuery {
-					continue
-				}
-				if err := parseTemplatedLabelPromQL(template, d.Templating.List); err != nil {
-					r.AddError(d, fmt.Sprintf("template '%s' invalid templated label '%s': %v", template.Name, template.Query, err))
-				}
+			if err := parseTemplatedLabelPromQL(t, d.Templating.List); err != nil {
+				r.AddError(d, t, fmt.Sprintf("invalid templated label '%s': %v", t.Query, err))
 			}
-
 			return r
 		},
 	}
@@ -2,6 +2,8 @@ package lint
 
 import (
 	"testing"
+
+	"github.com/stretchr/testify/require"
 )
 
 func TestTemplateLabelPromQLRule(t *testing.T) {
@@ -9,12 +11,12 @@ func TestTemplateLabelPromQLRule(t *testing.T) {
 
 	for _, tc := range []struct {
 		name      string
-		result    Result
+		results   []Result // one per template
 		dashboard Dashboard
 	}{
 		{
-			name:   "Don't fail on non prometheus template.",
-			result: ResultSuccess,
+			name:    "Don't fail on non prometheus template.",
+			results: []Result{ResultSuccess},
 			dashboard: Dashboard{
 				Title: "test",
 				Templating: struct {
@@ -30,8 +32,8 @@ func TestTemplateLabelPromQLRule(t *testing.T) {
 			},
 		},
 		{
-			name:   "OK",
-			result: ResultSuccess,
+			name:    "OK",
+			results: []Result{ResultSuccess, ResultSuccess},
 			dashboard: Dashboard{
 				Title: "test",
 				Templating: struct {
@@ -55,9 +57,12 @@ func TestTemplateLabelPromQLRule(t *testing.T) {
 		},
 		{
 			name: "Error",
-			result: Result{
-				Severity: Error,
-				Message:  `Dashboard 'test' template 'namespaces' invalid templated label 'label_values(up{, namespace)': 1:4: parse error: unexpected "," in label matching, expected identifier or "}"`,
+			results: []Result{
+				ResultSuccess,
+				{
+					Severity: Error,
+					Message:  `Dashboard 'test' template 'namespaces' invalid templated label 'label_values(up{, namespace)': 1:4: parse error: unexpected "," in label matching, expected identifier or "}"`,
+				},
 			},
 			dashboard: Dashboard{
 				Title: "test",
@@ -82,9 +87,12 @@ func TestTemplateLabelPromQLRule(t *testing.T) {
 		},
 		{
 			name: "Invalid function.",
-			result: Result{
-				Severity: Error,
-				Message:  `Dashboard 'test' template 'namespaces' invalid templated label 'foo(up, namespace)': invalid 'function': foo`,
+			results: []Result{
+				ResultSuccess,
+				{
+					Severity: Error,
+					Message:  `Dashboard 'test' template 'namespaces' invalid templated label 'foo(up, namespace)': invalid 'function': foo`,
+				},
 			},
 			dashboard: Dashboard{
 				Title: "test",
@@ -109,9 +117,12 @@ func TestTemplateLabelPromQLRule(t *testing.T) {
 		},
 		{
 			name: "Invalid query expression.",
-			result: Result{
-				Severity: Error,
-				Message:  `Dashboard 'test' template 'namespaces' invalid templated label 'foo': invalid 'query': foo`,
+			results: []Result{
+				ResultSuccess,
+				{
+					Severity: Error,
+					Message:  `Dashboard 'test' template 'namespaces' invalid templated label 'foo': invalid 'query': foo`,
+				},
 			},
 			dashboard: Dashboard{
 				Title: "test",
@@ -136,7 +147,7 @@ func TestTemplateLabelPromQLRule(t *testing.T) {
 		},
 		// Support main grafana variables.
 		{
-			result: ResultSuccess,
+			results: []Result{ResultSuccess, ResultSuccess},
 			dashboard: Dashboard{
 				Title: "test",
 				Templating: struct {
@@ -160,7 +171,13 @@ func TestTemplateLabelPromQLRule(t *testing.T) {
 		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
-			testRule(t, linter, tc.dashboard, tc.result)
+			rs := ResultSet{}
+			linter.Lint(tc.dashboard, &rs)
+			require.Len(t, rs.results, len(tc.dashboard.Templating.List))
+			for i, rc := range rs.results {
+				require.Equal(t, tc.dashboard.Templating.List[i].Name, rc.Template.Name)
+				requireResults(t, rc, []Result{tc.results[i]})
+			}
 		})
 	}
 }
@@ -4,32 +4,33 @@ import (
 	"fmt"
 )
 
-func NewTemplateOnTimeRangeReloadRule() *DashboardRuleFunc {
-	return &DashboardRuleFunc{
+func NewTemplateOnTimeRangeReloadRule() *TemplateRuleFunc {
+	return &TemplateRuleFunc{
 		name:        "template-on-time-change-reload-rule",
 		description: "Checks that the dashboard template variables are configured to reload on time change.",
-		fn: func(d Dashboard) DashboardRuleResults {
-			r := DashboardRuleResults{}
-
-			for i, template := range d.Templating.List {
-				if template.Type != targetTypeQuery {
-					continue
-				}
+		metadata: RuleMetadata{
+			Category: CategoryTemplate,
+			Severity: Error,
+			Fixable:  true,
+			DocsURL:  rulesDocsURL + "template-on-time-change-reload-rule.md",
+			Since:    firstRelease,
+		},
+		fn: func(d Dashboard, t Template) TemplateRuleResults {
+			r := TemplateRuleResults{}
+			if t.Type != targetTypeQuery {
+				return r
+			}
 
-				if template.Refresh != 2 {
-					r.AddFixableError(d,
-						fmt.Sprintf("templated datasource variable named '%s', should be set to be refreshed "+
-							"'On Time Range Change (value 2)', is currently '%d'", template.Name, template.Refresh),
-						fixTemplateOnTimeRangeReloadRule(i))
-				}
+			if t.Refresh != 2 {
+				r.AddFixableError(d, t,
+					fmt.Sprintf("should be set to be refreshed 'On Time Range Change (value 2)', is currently '%d'", t.Refresh),
+					fixTemplateOnTimeRangeReloadRule)
 			}
 			return r
 		},
 	}
 }
 
-func fixTemplateOnTimeRangeReloadRule(i int) func(*Dashboard) {
-	return func(d *Dashboard) {
-		d.Templating.List[i].Refresh = 2
-	}
+func fixTemplateOnTimeRangeReloadRule(d Dashboard, t *Template) {
+	t.Refresh = 2
 }
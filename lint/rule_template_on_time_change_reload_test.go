@@ -46,7 +46,7 @@ func TestTemplateOnTimeRangeReloadRule(t *testing.T) {
 			name: "autofix",
 			result: Result{
 				Severity: Fixed,
-				Message:  `Dashboard 'test' templated datasource variable named 'namespaces', should be set to be refreshed 'On Time Range Change (value 2)', is currently '1'`,
+				Message:  `Dashboard 'test' template 'namespaces' should be set to be refreshed 'On Time Range Change (value 2)', is currently '1'`,
 			},
 			dashboard: Dashboard{
 				Title: "test",
@@ -82,7 +82,7 @@ func TestTemplateOnTimeRangeReloadRule(t *testing.T) {
 			name: "error",
 			result: Result{
 				Severity: Error,
-				Message:  `Dashboard 'test' templated datasource variable named 'namespaces', should be set to be refreshed 'On Time Range Change (value 2)', is currently '1'`,
+				Message:  `Dashboard 'test' template 'namespaces' should be set to be refreshed 'On Time Range Change (value 2)', is currently '1'`,
 			},
 			dashboard: Dashboard{
 				Title: "test",
@@ -109,7 +109,7 @@ func TestTemplateOnTimeRangeReloadRule(t *testing.T) {
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			autofix := tc.fixed != nil
-			testRuleWithAutofix(t, linter, &tc.dashboard, []Result{tc.result}, autofix)
+			testTemplateRuleWithAutofix(t, linter, &tc.dashboard, "namespaces", []Result{tc.result}, autofix)
 			if autofix {
 				expected, _ := json.Marshal(tc.fixed)
 				actual, _ := json.Marshal(tc.dashboard)
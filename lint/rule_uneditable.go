@@ -4,6 +4,13 @@ func NewUneditableRule() *DashboardRuleFunc {
 	return &DashboardRuleFunc{
 		name:        "uneditable-dashboard",
 		description: "Checks that the dashboard is not editable.",
+		metadata: RuleMetadata{
+			Category: CategoryDashboard,
+			Severity: Error,
+			Fixable:  true,
+			DocsURL:  rulesDocsURL + "template-uneditable-rule.md",
+			Since:    firstRelease,
+		},
 		fn: func(d Dashboard) DashboardRuleResults {
 			r := DashboardRuleResults{}
 			if d.Editable {
@@ -1,24 +1,117 @@
 package lint
 
+import (
+	"context"
+	"fmt"
+	"path"
+	"slices"
+	"sync"
+)
+
 type Rule interface {
 	Description() string
 	Name() string
+	Metadata() RuleMetadata
 	Lint(Dashboard, *ResultSet)
 }
 
+// Rule categories, by the kind of dashboard element a rule checks.
+const (
+	CategoryDashboard  = "dashboard"
+	CategoryTemplate   = "template"
+	CategoryAnnotation = "annotation"
+	CategoryPanel      = "panel"
+	CategoryTarget     = "target"
+)
+
+// RuleMetadata describes what a rule checks, and how.
+type RuleMetadata struct {
+	// Category is the kind of dashboard element the rule checks, one of the Category constants.
+	Category string `json:"category"`
+	// Severity is the severity of the most severe problems the rule finds, before any configuration.
+	Severity Severity `json:"severity"`
+	// Fixable is true if the rule can fix the problems it finds.
+	Fixable bool `json:"fixable"`
+	// Datasources are the types of datasource the rule applies to, or empty if it applies to all of them.
+	Datasources []string `json:"datasources,omitempty"`
+	// DocsURL links to the documentation of the rule, if there is any.
+	DocsURL string `json:"docsUrl,omitempty"`
+	// Since is the version of the linter the rule was added in. It is empty for rules which are not built in.
+	Since string `json:"since,omitempty"`
+}
+
+// firstRelease is the first versioned release of the linter, which all rules predating versioned releases are
+// part of.
+const firstRelease = "v0.1.0"
+
+// withDefaults returns the metadata with the category of the rule type, and an error severity, if not set.
+func (m RuleMetadata) withDefaults(category string) RuleMetadata {
+	if m.Category == "" {
+		m.Category = category
+	}
+	if m.Severity == Success {
+		m.Severity = Error
+	}
+	return m
+}
+
+// appliesTo returns true if a rule applies to the dashboard, or to target if not nil, based on the types of
+// datasource it applies to. A rule applies if the templated datasource of the dashboard, or the datasource of
+// the target, is of one of those types.
+func (m RuleMetadata) appliesTo(d Dashboard, t *Target) bool {
+	if len(m.Datasources) == 0 {
+		return true
+	}
+	if templateDS := getTemplateDatasource(d); templateDS != nil && m.hasDatasource(templateDS.Query) {
+		return true
+	}
+	if t != nil {
+		if ds, err := t.GetDataSource(); err == nil && m.hasDatasource(ds.Type) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateDatasources returns an error if any of datasources is not a type of datasource rules can apply to.
+func validateDatasources(datasources []string) error {
+	for _, ds := range datasources {
+		if ds != Prometheus && ds != Loki {
+			return fmt.Errorf("invalid datasource '%s', must be one of prometheus, loki", ds)
+		}
+	}
+	return nil
+}
+
+func (m RuleMetadata) hasDatasource(dsType string) bool {
+	for _, ds := range m.Datasources {
+		if ds == dsType {
+			return true
+		}
+	}
+	return false
+}
+
 type DashboardRuleFunc struct {
 	name, description string
+	metadata          RuleMetadata
 	fn                func(Dashboard) DashboardRuleResults
 }
 
 func NewDashboardRuleFunc(name, description string, fn func(Dashboard) DashboardRuleResults) Rule {
-	return &DashboardRuleFunc{name, description, fn}
+	return &DashboardRuleFunc{name: name, description: description, fn: fn}
 }
 
 func (f DashboardRuleFunc) Name() string        { return f.name }
 func (f DashboardRuleFunc) Description() string { return f.description }
+func (f DashboardRuleFunc) Metadata() RuleMetadata {
+	return f.metadata.withDefaults(CategoryDashboard)
+}
 func (f DashboardRuleFunc) Lint(d Dashboard, s *ResultSet) {
-	dashboardResults := f.fn(d).Results
+	var dashboardResults []DashboardResult
+	if f.metadata.appliesTo(d, nil) {
+		dashboardResults = f.fn(d).Results
+	}
 	if len(dashboardResults) == 0 {
 		dashboardResults = []DashboardResult{{
 			Result: ResultSuccess,
@@ -46,27 +139,159 @@ func (f DashboardRuleFunc) Lint(d Dashboard, s *ResultSet) {
 		Result:    RuleResults{rr},
 		Rule:      f,
 		Dashboard: &d,
+		Location:  d.Location,
 	})
 }
 
+type TemplateRuleFunc struct {
+	name, description string
+	metadata          RuleMetadata
+	fn                func(Dashboard, Template) TemplateRuleResults
+}
+
+func NewTemplateRuleFunc(name, description string, fn func(Dashboard, Template) TemplateRuleResults) Rule {
+	return &TemplateRuleFunc{name: name, description: description, fn: fn}
+}
+
+func (f TemplateRuleFunc) Name() string        { return f.name }
+func (f TemplateRuleFunc) Description() string { return f.description }
+func (f TemplateRuleFunc) Metadata() RuleMetadata {
+	return f.metadata.withDefaults(CategoryTemplate)
+}
+func (f TemplateRuleFunc) Lint(d Dashboard, s *ResultSet) {
+	for ti, t := range d.Templating.List {
+		t := t   // capture loop variable
+		ti := ti // capture loop variable
+		var rr []FixableResult
+
+		var templateResults []TemplateResult
+		if f.metadata.appliesTo(d, nil) {
+			templateResults = f.fn(d, t).Results
+		}
+		if len(templateResults) == 0 {
+			templateResults = []TemplateResult{{
+				Result: ResultSuccess,
+			}}
+		}
+
+		for _, r := range templateResults {
+			var fix func(*Dashboard)
+			if r.Fix != nil {
+				fix = fixTemplate(ti, r)
+			}
+			rr = append(rr, FixableResult{
+				Result: Result{
+					Severity: r.Severity,
+					Message:  r.Message,
+				},
+				Fix: fix,
+			})
+		}
+
+		s.AddResult(ResultContext{
+			Result:    RuleResults{rr},
+			Rule:      f,
+			Dashboard: &d,
+			Template:  &t,
+			Location:  t.Location,
+		})
+	}
+}
+
+// fixTemplate returns a fix for the template at index ti of the dashboard's templating list.
+func fixTemplate(ti int, r TemplateResult) func(dashboard *Dashboard) {
+	return func(dashboard *Dashboard) {
+		r.Fix(*dashboard, &dashboard.Templating.List[ti])
+	}
+}
+
+type AnnotationRuleFunc struct {
+	name, description string
+	metadata          RuleMetadata
+	fn                func(Dashboard, Annotation) AnnotationRuleResults
+}
+
+func NewAnnotationRuleFunc(name, description string, fn func(Dashboard, Annotation) AnnotationRuleResults) Rule {
+	return &AnnotationRuleFunc{name: name, description: description, fn: fn}
+}
+
+func (f AnnotationRuleFunc) Name() string        { return f.name }
+func (f AnnotationRuleFunc) Description() string { return f.description }
+func (f AnnotationRuleFunc) Metadata() RuleMetadata {
+	return f.metadata.withDefaults(CategoryAnnotation)
+}
+func (f AnnotationRuleFunc) Lint(d Dashboard, s *ResultSet) {
+	for ai, a := range d.Annotations.List {
+		a := a   // capture loop variable
+		ai := ai // capture loop variable
+		var rr []FixableResult
+
+		var annotationResults []AnnotationResult
+		if f.metadata.appliesTo(d, nil) {
+			annotationResults = f.fn(d, a).Results
+		}
+		if len(annotationResults) == 0 {
+			annotationResults = []AnnotationResult{{
+				Result: ResultSuccess,
+			}}
+		}
+
+		for _, r := range annotationResults {
+			var fix func(*Dashboard)
+			if r.Fix != nil {
+				fix = fixAnnotation(ai, r)
+			}
+			rr = append(rr, FixableResult{
+				Result: Result{
+					Severity: r.Severity,
+					Message:  r.Message,
+				},
+				Fix: fix,
+			})
+		}
+
+		s.AddResult(ResultContext{
+			Result:     RuleResults{rr},
+			Rule:       f,
+			Dashboard:  &d,
+			Annotation: &a,
+			Location:   a.Location,
+		})
+	}
+}
+
+// fixAnnotation returns a fix for the annotation at index ai of the dashboard's annotation list.
+func fixAnnotation(ai int, r AnnotationResult) func(dashboard *Dashboard) {
+	return func(dashboard *Dashboard) {
+		r.Fix(*dashboard, &dashboard.Annotations.List[ai])
+	}
+}
+
 type PanelRuleFunc struct {
 	name, description string
+	metadata          RuleMetadata
 	fn                func(Dashboard, Panel) PanelRuleResults
 }
 
 func NewPanelRuleFunc(name, description string, fn func(Dashboard, Panel) PanelRuleResults) Rule {
-	return &PanelRuleFunc{name, description, fn}
+	return &PanelRuleFunc{name: name, description: description, fn: fn}
 }
 
 func (f PanelRuleFunc) Name() string        { return f.name }
 func (f PanelRuleFunc) Description() string { return f.description }
+func (f PanelRuleFunc) Metadata() RuleMetadata {
+	return f.metadata.withDefaults(CategoryPanel)
+}
 func (f PanelRuleFunc) Lint(d Dashboard, s *ResultSet) {
 	for pi, p := range d.GetPanels() {
 		p := p   // capture loop variable
 		pi := pi // capture loop variable
 		var rr []FixableResult
 
-		panelResults := f.fn(d, p).Results
+		var panelResults []PanelResult
+		if f.metadata.appliesTo(d, nil) {
+			panelResults = f.fn(d, p).Results
+		}
 		if len(panelResults) == 0 {
 			panelResults = []PanelResult{{
 				Result: ResultSuccess,
@@ -92,29 +317,35 @@ func (f PanelRuleFunc) Lint(d Dashboard, s *ResultSet) {
 			Rule:      f,
 			Dashboard: &d,
 			Panel:     &p,
+			Location:  p.Location,
 		})
 	}
 }
 
+// fixPanel returns a fix for the panel at index pi of Dashboard.GetPanels, which is applied in place wherever
+// the panel is nested in the dashboard.
 func fixPanel(pi int, r PanelResult) func(dashboard *Dashboard) {
 	return func(dashboard *Dashboard) {
-		p := dashboard.GetPanels()[pi]
-		r.Fix(*dashboard, &p)
-		dashboard.Panels[pi] = p
+		p := dashboard.panelRefs()[pi]
+		r.Fix(*dashboard, p)
 	}
 }
 
 type TargetRuleFunc struct {
 	name, description string
+	metadata          RuleMetadata
 	fn                func(Dashboard, Panel, Target) TargetRuleResults
 }
 
 func NewTargetRuleFunc(name, description string, fn func(Dashboard, Panel, Target) TargetRuleResults) Rule {
-	return &TargetRuleFunc{name, description, fn}
+	return &TargetRuleFunc{name: name, description: description, fn: fn}
 }
 
 func (f TargetRuleFunc) Name() string        { return f.name }
 func (f TargetRuleFunc) Description() string { return f.description }
+func (f TargetRuleFunc) Metadata() RuleMetadata {
+	return f.metadata.withDefaults(CategoryTarget)
+}
 func (f TargetRuleFunc) Lint(d Dashboard, s *ResultSet) {
 	for pi, p := range d.GetPanels() {
 		p := p   // capture loop variable
@@ -124,7 +355,10 @@ func (f TargetRuleFunc) Lint(d Dashboard, s *ResultSet) {
 			ti := ti // capture loop variable
 			var rr []FixableResult
 
-			targetResults := f.fn(d, p, t).Results
+			var targetResults []TargetResult
+			if f.metadata.appliesTo(d, &t) {
+				targetResults = f.fn(d, p, t).Results
+			}
 			if len(targetResults) == 0 {
 				targetResults = []TargetResult{{
 					Result: ResultSuccess,
@@ -150,18 +384,18 @@ func (f TargetRuleFunc) Lint(d Dashboard, s *ResultSet) {
 				Dashboard: &d,
 				Panel:     &p,
 				Target:    &t,
+				Location:  t.Location,
 			})
 		}
 	}
 }
 
+// fixTarget returns a fix for the target at index ti of the panel at index pi of Dashboard.GetPanels, which is
+// applied in place wherever the panel is nested in the dashboard.
 func fixTarget(pi int, ti int, r TargetResult) func(dashboard *Dashboard) {
 	return func(dashboard *Dashboard) {
-		p := dashboard.GetPanels()[pi]
-		t := p.Targets[ti]
-		r.Fix(*dashboard, p, &t)
-		p.Targets[ti] = t
-		dashboard.Panels[pi] = p
+		p := dashboard.panelRefs()[pi]
+		r.Fix(*dashboard, *p, &p.Targets[ti])
 	}
 }
 
@@ -202,12 +436,159 @@ func (s *RuleSet) Add(r Rule) {
 	s.rules = append(s.rules, r)
 }
 
-func (s *RuleSet) Lint(dashboards []Dashboard) (*ResultSet, error) {
-	resSet := &ResultSet{}
-	for _, d := range dashboards {
+// set replaces the rule with the name of r, or adds r if there is none.
+func (s *RuleSet) set(r Rule) {
+	i := slices.IndexFunc(s.rules, func(rule Rule) bool { return rule.Name() == r.Name() })
+	if i < 0 {
+		s.Add(r)
+		return
+	}
+	s.rules[i] = r
+}
+
+// RuleSelection selects which rules of a RuleSet are run. Rules are selected by name, or by a glob such as
+// target-* matching the names of a whole category of rules.
+type RuleSelection struct {
+	// Only, if not empty, disables all rules but the matching ones.
+	Only []string `yaml:"only,omitempty"`
+	// Disable disables the matching rules.
+	Disable []string `yaml:"disable,omitempty"`
+	// Enable enables the matching rules again, after Only and Disable are applied.
+	Enable []string `yaml:"enable,omitempty"`
+}
+
+// Select returns the rules which are enabled after applying each of the selections in turn, so later
+// selections override earlier ones. All rules are enabled to begin with. Patterns which don't match any
+// rule are an error, to catch typos.
+func (s *RuleSet) Select(selections ...RuleSelection) (RuleSet, error) {
+	enabled := make(map[string]bool, len(s.rules))
+	for _, r := range s.rules {
+		enabled[r.Name()] = true
+	}
+
+	for _, sel := range selections {
+		if len(sel.Only) > 0 {
+			only, err := s.matching(sel.Only)
+			if err != nil {
+				return RuleSet{}, err
+			}
+			for name := range enabled {
+				enabled[name] = only[name]
+			}
+		}
+		disable, err := s.matching(sel.Disable)
+		if err != nil {
+			return RuleSet{}, err
+		}
+		for name := range disable {
+			enabled[name] = false
+		}
+		enable, err := s.matching(sel.Enable)
+		if err != nil {
+			return RuleSet{}, err
+		}
+		for name := range enable {
+			enabled[name] = true
+		}
+	}
+
+	selected := RuleSet{}
+	for _, r := range s.rules {
+		if enabled[r.Name()] {
+			selected.Add(r)
+		}
+	}
+	return selected, nil
+}
+
+// matching returns the names of the rules matching any of patterns.
+func (s *RuleSet) matching(patterns []string) (map[string]bool, error) {
+	names := make(map[string]bool)
+	for _, pattern := range patterns {
+		found := false
 		for _, r := range s.rules {
-			r.Lint(d, resSet)
+			ok, err := path.Match(pattern, r.Name())
+			if err != nil {
+				return nil, fmt.Errorf("invalid rule pattern '%s': %w", pattern, err)
+			}
+			if ok {
+				names[r.Name()] = true
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("no rule matches '%s'", pattern)
 		}
 	}
+	return names, nil
+}
+
+// Lint lints all dashboards with all rules of the set, one after the other.
+func (s *RuleSet) Lint(dashboards []Dashboard) (*ResultSet, error) {
+	return s.LintConcurrently(dashboards, 1)
+}
+
+// LintConcurrently lints all dashboards with all rules of the set, running up to jobs rules at once. The results
+// are the same as the ones of Lint, and in the same order, whatever order the rules complete in.
+func (s *RuleSet) LintConcurrently(dashboards []Dashboard, jobs int) (*ResultSet, error) {
+	return s.lintContext(context.Background(), dashboards, jobs)
+}
+
+// lintContext is LintConcurrently, stopping early with the error of ctx once it is done.
+func (s *RuleSet) lintContext(ctx context.Context, dashboards []Dashboard, jobs int) (*ResultSet, error) {
+	dashboards = append([]Dashboard{}, dashboards...)
+	for i := range dashboards {
+		// each query of the dashboard is parsed once, for all rules
+		dashboards[i].parsed = newParseCache()
+	}
+	parts := make([]ResultSet, len(dashboards)*len(s.rules))
+	err := forEach(ctx, len(parts), jobs, func(i int) {
+		d, r := dashboards[i/len(s.rules)], s.rules[i%len(s.rules)]
+		r.Lint(d, &parts[i])
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resSet := &ResultSet{}
+	for _, part := range parts {
+		resSet.results = append(resSet.results, part.results...)
+	}
 	return resSet, nil
 }
+
+// forEach calls fn with every index up to n, running up to jobs calls at once. Once ctx is done, fn isn't called
+// anymore, and the error of ctx is returned.
+func forEach(ctx context.Context, n, jobs int, fn func(i int)) error {
+	if jobs <= 1 {
+		for i := 0; i < n; i++ {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			fn(i)
+		}
+		return nil
+	}
+
+	var wg sync.WaitGroup
+	next := make(chan int)
+	for j := 0; j < min(jobs, n); j++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				fn(i)
+			}
+		}()
+	}
+	defer wg.Wait()
+	defer close(next)
+	for i := 0; i < n; i++ {
+		select {
+		case next <- i:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
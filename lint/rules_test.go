@@ -1,7 +1,11 @@
 package lint_test
 
 import (
+	"bytes"
+	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/grafana/dashboard-linter/lint"
@@ -38,6 +42,28 @@ func TestCustomRules(t *testing.T) {
 				},
 			),
 		},
+		{
+			desc: "Should allow addition of template rule",
+			rule: lint.NewTemplateRuleFunc(
+				"test-template-rule", "Test template rule",
+				func(lint.Dashboard, lint.Template) lint.TemplateRuleResults {
+					return lint.TemplateRuleResults{Results: []lint.TemplateResult{{
+						Result: lint.Result{Severity: lint.Error, Message: "Error found"},
+					}}}
+				},
+			),
+		},
+		{
+			desc: "Should allow addition of annotation rule",
+			rule: lint.NewAnnotationRuleFunc(
+				"test-annotation-rule", "Test annotation rule",
+				func(lint.Dashboard, lint.Annotation) lint.AnnotationRuleResults {
+					return lint.AnnotationRuleResults{Results: []lint.AnnotationResult{{
+						Result: lint.Result{Severity: lint.Error, Message: "Error found"},
+					}}}
+				},
+			),
+		},
 		{
 			desc: "Should allow addition of target rule",
 			rule: lint.NewTargetRuleFunc(
@@ -99,3 +125,235 @@ func TestFixableRules(t *testing.T) {
 
 	assert.Equal(t, "Sample dashboard fixed-once fixed-twice", dashboard.Title)
 }
+
+func TestFixableNestedPanels(t *testing.T) {
+	sampleDashboard, err := os.ReadFile("testdata/dashboard.json")
+	assert.NoError(t, err)
+
+	for _, tc := range []struct {
+		desc string
+		rule lint.Rule
+	}{
+		{
+			desc: "Should fix panels in rows and collapsed rows",
+			rule: lint.NewPanelRuleFunc(
+				"test-fixable-panel-rule", "Test fixable panel rule",
+				func(d lint.Dashboard, p lint.Panel) lint.PanelRuleResults {
+					return lint.PanelRuleResults{Results: []lint.PanelResult{{
+						Result: lint.Result{Severity: lint.Error, Message: "no description"},
+						Fix: func(d lint.Dashboard, p *lint.Panel) {
+							p.Description = "fixed " + p.Title
+						},
+					}}}
+				},
+			),
+		},
+		{
+			desc: "Should fix targets in rows and collapsed rows",
+			rule: lint.NewTargetRuleFunc(
+				"test-fixable-target-rule", "Test fixable target rule",
+				func(d lint.Dashboard, p lint.Panel, t lint.Target) lint.TargetRuleResults {
+					return lint.TargetRuleResults{Results: []lint.TargetResult{{
+						Result: lint.Result{Severity: lint.Error, Message: "bad expression"},
+						Fix: func(d lint.Dashboard, p lint.Panel, t *lint.Target) {
+							t.Expr = "fixed " + p.Title
+						},
+					}}}
+				},
+			),
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			rules := lint.RuleSet{}
+			rules.Add(tc.rule)
+
+			dashboard, err := lint.NewDashboard(sampleDashboard)
+			assert.NoError(t, err)
+
+			results, err := rules.Lint([]lint.Dashboard{dashboard})
+			assert.NoError(t, err)
+
+			changes := results.AutoFix(&dashboard)
+			assert.Greater(t, changes, 0)
+
+			panels := dashboard.GetPanels()
+			assert.Len(t, panels, 4)
+			for _, p := range panels {
+				switch tc.rule.(type) {
+				case *lint.PanelRuleFunc:
+					assert.Equal(t, "fixed "+p.Title, p.Description)
+				case *lint.TargetRuleFunc:
+					for _, target := range p.Targets {
+						assert.Equal(t, "fixed "+p.Title, target.Expr)
+					}
+				}
+			}
+			assert.Equal(t, "Timeseries", dashboard.Rows[0].Panels[0].Title)
+			assert.Equal(t, "Dashboard row", dashboard.Panels[1].Title)
+		})
+	}
+}
+
+func TestRuleSetSelect(t *testing.T) {
+	rules := lint.NewRuleSet()
+
+	for _, tc := range []struct {
+		desc       string
+		selections []lint.RuleSelection
+		expected   []string
+		err        string
+	}{
+		{
+			desc: "Should keep all rules without selections",
+			expected: []string{
+				"template-datasource-rule", "template-job-rule", "template-instance-rule", "template-label-promql-rule",
+				"template-on-time-change-reload-rule", "panel-datasource-rule", "panel-title-description-rule",
+				"panel-units-rule", "panel-no-targets-rule", "target-logql-rule", "target-logql-auto-rule",
+				"target-promql-rule", "target-rate-interval-rule", "target-job-rule", "target-instance-rule",
+				"target-counter-agg-rule", "uneditable-dashboard",
+			},
+		},
+		{
+			desc:       "Should only keep matching rules",
+			selections: []lint.RuleSelection{{Only: []string{"panel-*", "uneditable-dashboard"}}},
+			expected:   []string{"panel-datasource-rule", "panel-title-description-rule", "panel-units-rule", "panel-no-targets-rule", "uneditable-dashboard"},
+		},
+		{
+			desc:       "Should disable matching rules and enable them again",
+			selections: []lint.RuleSelection{{Only: []string{"target-*"}, Disable: []string{"target-logql*"}, Enable: []string{"target-logql-rule"}}},
+			expected:   []string{"target-logql-rule", "target-promql-rule", "target-rate-interval-rule", "target-job-rule", "target-instance-rule", "target-counter-agg-rule"},
+		},
+		{
+			desc: "Should let later selections override earlier ones",
+			selections: []lint.RuleSelection{
+				{Disable: []string{"template-*", "panel-*", "target-*"}},
+				{Only: []string{"panel-units-rule"}, Enable: []string{"target-promql-rule"}},
+			},
+			expected: []string{"panel-units-rule", "target-promql-rule"},
+		},
+		{
+			desc:       "Should fail for patterns not matching any rule",
+			selections: []lint.RuleSelection{{Disable: []string{"panel-*", "pannel-units-rule"}}},
+			err:        "no rule matches 'pannel-units-rule'",
+		},
+		{
+			desc:       "Should fail for invalid patterns",
+			selections: []lint.RuleSelection{{Only: []string{"panel-["}}},
+			err:        "invalid rule pattern 'panel-[': syntax error in pattern",
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			selected, err := rules.Select(tc.selections...)
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				return
+			}
+			assert.NoError(t, err)
+
+			var names []string
+			for _, r := range selected.Rules() {
+				names = append(names, r.Name())
+			}
+			assert.Equal(t, tc.expected, names)
+		})
+	}
+}
+
+func TestRuleMetadata(t *testing.T) {
+	rules := lint.NewRuleSet()
+	for _, rule := range rules.Rules() {
+		t.Run(rule.Name(), func(t *testing.T) {
+			metadata := rule.Metadata()
+			assert.Contains(t, []string{lint.CategoryDashboard, lint.CategoryTemplate, lint.CategoryPanel, lint.CategoryTarget}, metadata.Category)
+			if metadata.Category != lint.CategoryDashboard {
+				assert.True(t, strings.HasPrefix(rule.Name(), metadata.Category+"-"))
+			}
+			assert.Equal(t, lint.Error, metadata.Severity)
+			assert.NotEmpty(t, metadata.Since, "built-in rules should have the version they were added in")
+
+			if metadata.DocsURL != "" {
+				// Links have to point to the docs of this repository
+				doc := strings.TrimPrefix(metadata.DocsURL, "https://github.com/grafana/dashboard-linter/blob/main/")
+				assert.FileExists(t, filepath.Join("..", doc))
+			}
+		})
+	}
+
+	fixable := map[string]bool{}
+	for _, rule := range rules.Rules() {
+		if rule.Metadata().Fixable {
+			fixable[rule.Name()] = true
+		}
+	}
+	assert.Equal(t, map[string]bool{"template-on-time-change-reload-rule": true, "uneditable-dashboard": true}, fixable)
+
+	t.Run("Custom rules get the defaults of their type", func(t *testing.T) {
+		rule := lint.NewPanelRuleFunc("test-panel-rule", "Test panel rule", func(lint.Dashboard, lint.Panel) lint.PanelRuleResults {
+			return lint.PanelRuleResults{}
+		})
+		assert.Equal(t, lint.RuleMetadata{Category: lint.CategoryPanel, Severity: lint.Error}, rule.Metadata())
+	})
+}
+
+func TestDatasourceRouting(t *testing.T) {
+	for _, tc := range []struct {
+		rule lint.Rule
+		expr string
+	}{
+		{rule: lint.NewTargetPromQLRule(), expr: "sum(rate(foo[5m]"},
+		{rule: lint.NewTargetCounterAggRule(), expr: "sum(foo_total)"},
+	} {
+		t.Run(tc.rule.Name(), func(t *testing.T) {
+			dashboard, err := lint.NewDashboard([]byte(fmt.Sprintf(`{
+  "title": "dashboard",
+  "templating": {"list": [{"type": "datasource", "name": "datasource", "query": "loki"}]},
+  "panels": [{
+    "title": "panel",
+    "type": "timeseries",
+    "targets": [
+      {"expr": %[1]q, "datasource": {"type": "prometheus", "uid": "prom"}},
+      {"expr": %[1]q}
+    ]
+  }]
+}`, tc.expr)))
+			assert.NoError(t, err)
+
+			rules := lint.RuleSet{}
+			rules.Add(tc.rule)
+			results, err := rules.Lint([]lint.Dashboard{dashboard})
+			assert.NoError(t, err)
+
+			// Only the target using a Prometheus datasource is checked, the other one uses the templated Loki datasource
+			rs := results.ByRule()[tc.rule.Name()]
+			assert.Len(t, rs, 2)
+			assert.Equal(t, lint.Error, rs[0].Result.Results[0].Severity)
+			assert.Equal(t, lint.Success, rs[1].Result.Results[0].Severity)
+		})
+	}
+}
+
+func TestRuleSetLintConcurrently(t *testing.T) {
+	sampleDashboard, err := os.ReadFile("testdata/dashboard.json")
+	assert.NoError(t, err)
+
+	var dashboards []lint.Dashboard
+	for _, title := range []string{"dash1", "dash2", "dash3"} {
+		dashboard, err := lint.NewDashboard(sampleDashboard)
+		assert.NoError(t, err)
+		dashboard.Title = title
+		dashboards = append(dashboards, dashboard)
+	}
+	rules := lint.NewRuleSet()
+
+	report := func(jobs int) string {
+		results, err := rules.LintConcurrently(dashboards, jobs)
+		assert.NoError(t, err)
+		var buf bytes.Buffer
+		assert.NoError(t, results.ReportJSON(&buf))
+		return buf.String()
+	}
+	sequential := report(1)
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, sequential, report(8), "results should not depend on the order rules complete in")
+	}
+}
@@ -0,0 +1,106 @@
+package lint
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+const (
+	// disableTagPrefix starts the dashboard tags disabling rules, e.g. lint:disable=panel-units-rule.
+	disableTagPrefix = "lint:disable="
+	// reasonSeparator separates the rules disabled in a panel description from the reason they are.
+	reasonSeparator = " -- "
+)
+
+// disableDirectiveRegexp matches the directives disabling rules in panel descriptions, e.g.
+// lint-disable: target-instance-rule -- The panel shows all instances. They may be wrapped in a HTML comment, so
+// they don't show in the description.
+var disableDirectiveRegexp = regexp.MustCompile(`lint-disable:[ \t]*([^\n]*?)[ \t]*(?:-->.*)?$`)
+
+// suppression is a set of rules disabled inline in a dashboard, next to the elements they are about.
+type suppression struct {
+	rules  []string
+	reason string
+	// source describes where the rules were disabled.
+	source string
+}
+
+// matches returns true if rule is one of the rules disabled, or matches one of their globs.
+func (s suppression) matches(rule string) bool {
+	for _, pattern := range s.rules {
+		if ok, err := path.Match(pattern, rule); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
+// suppressionOf returns the inline suppression of the rule of res, from the panel res is about or its dashboard.
+func suppressionOf(res ResultContext) (suppression, bool) {
+	var all []suppression
+	if res.Panel != nil {
+		all = append(all, panelSuppressions(*res.Panel)...)
+	}
+	if res.Dashboard != nil {
+		all = append(all, dashboardSuppressions(*res.Dashboard)...)
+	}
+	for _, s := range all {
+		if s.matches(res.Rule.Name()) {
+			return s, true
+		}
+	}
+	return suppression{}, false
+}
+
+// dashboardSuppressions returns the rules disabled by the tags of d, which can give the reason like panel
+// descriptions, e.g. lint:disable=panel-units-rule -- Units vary.
+func dashboardSuppressions(d Dashboard) []suppression {
+	var suppressions []suppression
+	for _, tag := range d.Tags {
+		disabled, ok := strings.CutPrefix(tag, disableTagPrefix)
+		if !ok {
+			continue
+		}
+		rules, reason, _ := strings.Cut(disabled, reasonSeparator)
+		suppressions = append(suppressions, suppression{
+			rules:  splitRules(rules),
+			reason: strings.TrimSpace(reason),
+			source: fmt.Sprintf("dashboard tag '%s'", tag),
+		})
+	}
+	return suppressions
+}
+
+// panelSuppressions returns the rules disabled by the lint field and the description of p.
+func panelSuppressions(p Panel) []suppression {
+	var suppressions []suppression
+	if p.Lint != nil && len(p.Lint.Disable) > 0 {
+		suppressions = append(suppressions, suppression{
+			rules:  p.Lint.Disable,
+			reason: p.Lint.Reason,
+			source: "panel lint field",
+		})
+	}
+	for _, line := range strings.Split(p.Description, "\n") {
+		m := disableDirectiveRegexp.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		rules, reason, _ := strings.Cut(m[1], reasonSeparator)
+		suppressions = append(suppressions, suppression{
+			rules:  splitRules(rules),
+			reason: strings.TrimSpace(reason),
+			source: "panel description",
+		})
+	}
+	return suppressions
+}
+
+// splitRules splits a list of rules separated by commas or spaces.
+func splitRules(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+}
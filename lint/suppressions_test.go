@@ -0,0 +1,148 @@
+package lint
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuppressions(t *testing.T) {
+	dashboard, err := NewDashboard([]byte(`{
+		"title": "dash1",
+		"tags": ["team-a", "lint:disable=template-job-rule,template-instance-rule", "lint:disable=template-datasource-rule -- Uses a fixed datasource"],
+		"panels": [
+			{
+				"id": 1,
+				"title": "panel1",
+				"description": "CPU usage of all instances.\n<!-- lint-disable: target-instance-rule -- Shows all instances -->",
+				"targets": [{"refId": "A", "expr": "sum(rate(cpu[5m]))"}]
+			},
+			{
+				"id": 2,
+				"title": "panel2",
+				"lint": {"disable": ["panel-*"], "reason": "Unitless"}
+			}
+		]
+	}`))
+	require.NoError(t, err)
+	require.Equal(t, []string{"team-a", "lint:disable=template-job-rule,template-instance-rule", "lint:disable=template-datasource-rule -- Uses a fixed datasource"}, dashboard.Tags)
+	panels := dashboard.GetPanels()
+
+	for _, tc := range []struct {
+		desc        string
+		result      ResultContext
+		expected    Result
+		reason      string
+		suppression string
+	}{
+		{
+			desc: "Should exclude rules disabled by a tag of the dashboard",
+			result: ResultContext{
+				Rule:      &TestRule{name: "template-instance-rule"},
+				Dashboard: &dashboard,
+				Result:    newRuleResults(Result{Severity: Error, Message: "foo"}),
+			},
+			expected:    Result{Severity: Exclude, Message: "foo (Excluded by dashboard tag 'lint:disable=template-job-rule,template-instance-rule')"},
+			suppression: "dashboard tag 'lint:disable=template-job-rule,template-instance-rule'",
+		},
+		{
+			desc: "Should record the reason given in a tag of the dashboard",
+			result: ResultContext{
+				Rule:      &TestRule{name: "template-datasource-rule"},
+				Dashboard: &dashboard,
+				Result:    newRuleResults(Result{Severity: Error, Message: "foo"}),
+			},
+			expected:    Result{Severity: Exclude, Message: "foo (Excluded by dashboard tag 'lint:disable=template-datasource-rule -- Uses a fixed datasource')"},
+			reason:      "Uses a fixed datasource",
+			suppression: "dashboard tag 'lint:disable=template-datasource-rule -- Uses a fixed datasource'",
+		},
+		{
+			desc: "Should exclude rules disabled in the description of a panel",
+			result: ResultContext{
+				Rule:      &TestRule{name: "target-instance-rule"},
+				Dashboard: &dashboard,
+				Panel:     &panels[0],
+				Target:    &panels[0].Targets[0],
+				Result:    newRuleResults(Result{Severity: Warning, Message: "foo"}),
+			},
+			expected:    Result{Severity: Exclude, Message: "foo (Excluded by panel description)"},
+			reason:      "Shows all instances",
+			suppression: "panel description",
+		},
+		{
+			desc: "Should exclude rules disabled in the lint field of a panel",
+			result: ResultContext{
+				Rule:      &TestRule{name: "panel-units-rule"},
+				Dashboard: &dashboard,
+				Panel:     &panels[1],
+				Result:    newRuleResults(Result{Severity: Error, Message: "foo"}),
+			},
+			expected:    Result{Severity: Exclude, Message: "foo (Excluded by panel lint field)"},
+			reason:      "Unitless",
+			suppression: "panel lint field",
+		},
+		{
+			desc: "Should only exclude rules of the panel they are disabled for",
+			result: ResultContext{
+				Rule:      &TestRule{name: "panel-units-rule"},
+				Dashboard: &dashboard,
+				Panel:     &panels[0],
+				Result:    newRuleResults(Result{Severity: Error, Message: "foo"}),
+			},
+			expected: Result{Severity: Error, Message: "foo"},
+		},
+		{
+			desc: "Should not change successes",
+			result: ResultContext{
+				Rule:      &TestRule{name: "template-job-rule"},
+				Dashboard: &dashboard,
+				Result:    newRuleResults(Result{Severity: Success, Message: "OK"}),
+			},
+			expected: Result{Severity: Success, Message: "OK"},
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			config := NewConfigurationFile()
+			config.Verbose = true
+			res := config.Apply(tc.result)
+			require.Equal(t, tc.expected, res.Result.Results[0].Result)
+			require.Equal(t, tc.reason, res.Reason)
+			require.Equal(t, tc.suppression, res.Suppression)
+		})
+	}
+
+	t.Run("Should take precedence over the configuration", func(t *testing.T) {
+		config := NewConfigurationFile()
+		config.Warnings = map[string]*ConfigurationRuleEntries{
+			"template-job-rule": {Reason: "Being migrated"},
+		}
+		res := config.Apply(ResultContext{
+			Rule:      &TestRule{name: "template-job-rule"},
+			Dashboard: &dashboard,
+			Result:    newRuleResults(Result{Severity: Error, Message: "foo"}),
+		})
+		require.Equal(t, Exclude, res.Result.Results[0].Severity)
+		require.Empty(t, res.Reason)
+	})
+
+	t.Run("Should not fix the problems of disabled rules", func(t *testing.T) {
+		rules := RuleSet{}
+		rules.Add(NewUneditableRule())
+		linter := NewLinter(WithRules(rules), WithFix(true))
+
+		res, err := linter.LintReader(context.Background(), "dashboard.json", strings.NewReader(`{
+			"title": "dash1",
+			"editable": true,
+			"tags": ["lint:disable=uneditable-dashboard -- Edited in Grafana"]
+		}`))
+		require.NoError(t, err)
+		require.Zero(t, res.Fixes)
+		require.Nil(t, res.Fixed)
+		rc := res.Results.ByRule()["uneditable-dashboard"][0]
+		require.Equal(t, Exclude, rc.Result.Results[0].Severity)
+		require.Equal(t, "Edited in Grafana", rc.Reason)
+		require.Equal(t, "dashboard tag 'lint:disable=uneditable-dashboard -- Edited in Grafana'", rc.Suppression)
+	})
+}
@@ -2,26 +2,34 @@ package lint
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/prometheus/prometheus/model/labels"
 )
 
-func checkForMatcher(selector []*labels.Matcher, name string, ty labels.MatchType, value string) error {
+// checkForMatcher returns an error if selector doesn't have a matcher of the label of m, with one of its match
+// types and its value. Variables match however they are written, e.g. both $job and ${job}.
+func checkForMatcher(selector []*labels.Matcher, m requiredMatcher) error {
 	for _, matcher := range selector {
-		if matcher.Name != name {
+		if matcher.Name != m.label {
 			continue
 		}
 
-		if matcher.Type != ty {
-			return fmt.Errorf("%s selector is %s, not %s", name, matcher.Type, ty)
+		if !slices.Contains(m.types, matcher.Type) {
+			types := make([]string, 0, len(m.types))
+			for _, ty := range m.types {
+				types = append(types, ty.String())
+			}
+			return fmt.Errorf("%s selector is %s, not %s", m.label, matcher.Type, strings.Join(types, " or "))
 		}
 
-		if matcher.Value != value {
-			return fmt.Errorf("%s selector is %s, not %s", name, matcher.Value, value)
+		if matcher.Value != m.value && (m.variable == "" || variableName(matcher.Value) != m.variable) {
+			return fmt.Errorf("%s selector is %s, not %s", m.label, matcher.Value, m.value)
 		}
 
 		return nil
 	}
 
-	return fmt.Errorf("%s selector not found", name)
+	return fmt.Errorf("%s selector not found", m.label)
 }
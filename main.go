@@ -1,15 +1,19 @@
 package main
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
 	"path"
-	"strings"
+	"runtime"
+	"sort"
+	"sync"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
-	"github.com/zeitlinger/conflate"
 
 	"github.com/grafana/dashboard-linter/lint"
 )
@@ -17,125 +21,589 @@ import (
 var lintStrictFlag bool
 var lintVerboseFlag bool
 var lintAutofixFlag bool
+var lintDryRunFlag bool
+var lintMaxWarningsFlag int
 var lintReadFromStdIn bool
 var lintConfigFlag string
+var lintIncludeFlag []string
+var lintExcludeFlag []string
+var lintFormatFlag string
+var lintJUnitWarningsFlag string
+var lintBaselineFlag string
+var lintReportUnusedConfigFlag bool
+var lintJobsFlag int
+var lintColorFlag string
+var lintGroupByFlag string
+var lintOnlyFlag []string
+var lintDisableFlag []string
+var lintEnableFlag []string
+var baselineOutputFlag string
+var rulesConfigFlag string
+var rulesFormatFlag string
 
 // lintCmd represents the lint command
 var lintCmd = &cobra.Command{
-	Use:   "lint [dashboard.json]",
-	Short: "Lint a dashboard",
-	Long:  `Returns warnings or errors for dashboard which do not adhere to accepted standards`,
+	Use:   "lint [dashboard.json|directory]...",
+	Short: "Lint dashboards",
+	Long: `Returns warnings or errors for dashboards which do not adhere to accepted standards.
+
+Any number of dashboard files and directories can be given. Directories are walked recursively,
+linting every file matching --include and not matching --exclude.`,
 	PreRun: func(cmd *cobra.Command, args []string) {
 		_ = viper.BindPFlags(cmd.PersistentFlags())
 	},
 	SilenceUsage: true,
-	Args:         cobra.RangeArgs(0, 1),
+	Args:         cobra.ArbitraryArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		var buf []byte
-		var err error
-		var filename string
-
-		if lintReadFromStdIn {
-			if lintAutofixFlag {
-				return fmt.Errorf("can't read from stdin and autofix")
-			}
-
-			buf, err = io.ReadAll(os.Stdin)
-			if err != nil {
-				return fmt.Errorf("failed to read stdin: %v", err)
-			}
-		} else {
-			filename = args[0]
-			buf, err = os.ReadFile(filename)
-			if err != nil {
-				return fmt.Errorf("failed to read file %s: %v", filename, err)
-			}
+		switch lintFormatFlag {
+		case "text", "compact", "json", "sarif", "junit", "github", "gitlab":
+		default:
+			return fmt.Errorf("unknown output format '%s', must be one of text, compact, json, sarif, junit, github, gitlab", lintFormatFlag)
 		}
-
-		dashboard, err := lint.NewDashboard(buf)
-		if err != nil {
-			return fmt.Errorf("failed to parse dashboard: %v", err)
+		if lintColorFlag != "auto" && lintColorFlag != "always" && lintColorFlag != "never" {
+			return fmt.Errorf("unknown color mode '%s', must be one of auto, always, never", lintColorFlag)
 		}
-
-		// if no config flag was passed, set a default path of a .lint file in the dashboards directory
-		if lintConfigFlag == "" {
-			lintConfigFlag = path.Join(path.Dir(filename), ".lint")
+		switch lint.GroupBy(lintGroupByFlag) {
+		case lint.GroupByRule, lint.GroupByDashboard, lint.GroupByFile:
+		default:
+			return fmt.Errorf("unknown grouping '%s', must be one of rule, dashboard, file", lintGroupByFlag)
+		}
+		if lintJUnitWarningsFlag != "failure" && lintJUnitWarningsFlag != "skipped" {
+			return fmt.Errorf("unknown junit warnings mode '%s', must be one of failure, skipped", lintJUnitWarningsFlag)
 		}
 
-		config := lint.NewConfigurationFile()
-		if err := config.Load(lintConfigFlag); err != nil {
-			return fmt.Errorf("failed to load lint config: %v", err)
+		if lintDryRunFlag && !lintAutofixFlag {
+			return fmt.Errorf("--dry-run can only be used with --fix")
 		}
-		config.Verbose = lintVerboseFlag
-		config.Autofix = lintAutofixFlag
 
-		rules := lint.NewRuleSet()
-		results, err := rules.Lint([]lint.Dashboard{dashboard})
+		configs := map[string]*lint.ConfigurationFile{}
+		results, total, failed, err := lintArgs(args, configs)
 		if err != nil {
-			return fmt.Errorf("failed to lint dashboard: %v", err)
+			return err
 		}
 
-		if config.Autofix {
-			changes := results.AutoFix(&dashboard)
-			if changes > 0 {
-				err = write(dashboard, filename, buf)
-				if err != nil {
-					return err
+		if lintBaselineFlag != "" {
+			baseline := &lint.Baseline{}
+			if err := baseline.Load(lintBaselineFlag); err != nil {
+				return fmt.Errorf("failed to load baseline: %v", err)
+			}
+			if fixed := results.ApplyBaseline(baseline); len(fixed) > 0 {
+				fmt.Fprintf(os.Stderr, "%d baseline entries were not found, as they were fixed, run the baseline command to remove them:\n", len(fixed))
+				for _, e := range fixed {
+					fmt.Fprintf(os.Stderr, "  %s\n", e)
 				}
 			}
 		}
 
-		results.Configure(config)
-		results.ReportByRule()
+		// With --dry-run, stdout is left for the fixes, so they can be piped on
+		out := io.Writer(os.Stdout)
+		if lintDryRunFlag {
+			out = os.Stderr
+		}
+		if err := reporter(out, configuredRules(configs)).Report(out, results); err != nil {
+			return fmt.Errorf("failed to report results: %v", err)
+		}
 
+		if failed > 0 {
+			return fmt.Errorf("failed to lint %d of %d dashboards, please see previous output", failed, total)
+		}
+		unused := 0
+		if lintReportUnusedConfigFlag {
+			unused = reportUnusedConfig(configs)
+		}
 		if lintStrictFlag && results.MaximumSeverity() >= lint.Warning {
 			return fmt.Errorf("there were linting errors, please see previous output")
 		}
+		if warnings := results.Count(lint.Warning); lintMaxWarningsFlag >= 0 && warnings > lintMaxWarningsFlag {
+			return fmt.Errorf("found %d warnings, more than the maximum of %d", warnings, lintMaxWarningsFlag)
+		}
+		if lintStrictFlag && unused > 0 {
+			return fmt.Errorf("found %d unused configuration entries, please see previous output", unused)
+		}
 		return nil
 	},
 }
 
-func write(dashboard lint.Dashboard, filename string, old []byte) error {
-	newBytes, err := dashboard.Marshal()
+// lintArgs lints the dashboards found in the files and directories given as args, or read from stdin. Dashboards
+// which can't be linted are reported to stderr, and counted as failed, along with the total of dashboards found.
+// The configuration files used are cached in configs.
+func lintArgs(args []string, configs map[string]*lint.ConfigurationFile) (results *lint.ResultSet, total int, failed int, err error) {
+	results = &lint.ResultSet{}
+
+	if lintReadFromStdIn {
+		if lintAutofixFlag && !lintDryRunFlag {
+			return nil, 0, 0, fmt.Errorf("can't read from stdin and autofix, use --dry-run to print the fixed dashboard instead")
+		}
+		if len(args) > 0 {
+			return nil, 0, 0, fmt.Errorf("can't read from stdin and lint files")
+		}
+
+		buf, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, 0, 0, fmt.Errorf("failed to read stdin: %v", err)
+		}
+		fileResults, err := lintDashboard("", buf, configs, jobs(), os.Stdout)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		results.Merge("", fileResults)
+		return results, 1, 0, nil
+	}
+
+	if len(args) == 0 {
+		return nil, 0, 0, fmt.Errorf("no dashboards to lint, pass files or directories, or use --stdin")
+	}
+
+	filenames, err := lint.FindDashboards(args, lintIncludeFlag, lintExcludeFlag)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("failed to find dashboards: %v", err)
+	}
+
+	// dashboards are linted concurrently, but their results, errors and diffs are reported in order
+	type linted struct {
+		results *lint.ResultSet
+		out     bytes.Buffer
+		err     error
+	}
+	all := make([]linted, len(filenames))
+	fileJobs, ruleJobs := splitJobs(jobs(), len(filenames))
+	forEach(len(filenames), fileJobs, func(i int) {
+		all[i].results, all[i].err = lintFile(filenames[i], configs, ruleJobs, &all[i].out)
+	})
+	for i, filename := range filenames {
+		_, _ = all[i].out.WriteTo(os.Stdout)
+		if all[i].err != nil {
+			fmt.Fprintln(os.Stderr, all[i].err)
+			failed++
+			continue
+		}
+		results.Merge(filename, all[i].results)
+	}
+	return results, len(filenames), failed, nil
+}
+
+// jobs returns how many dashboards and rules to lint at once.
+func jobs() int {
+	if lintJobsFlag > 0 {
+		return lintJobsFlag
+	}
+	return runtime.NumCPU()
+}
+
+// splitJobs splits jobs between linting n dashboards at once, and the rules of each of them, so no more than jobs
+// run at once in total. Dashboards get the jobs first, and the rules of each the ones left over.
+func splitJobs(jobs, n int) (dashboards, rules int) {
+	dashboards = max(1, min(jobs, n))
+	return dashboards, max(1, jobs/dashboards)
+}
+
+// forEach calls fn with every index up to n, running up to jobs calls at once.
+func forEach(n, jobs int, fn func(i int)) {
+	var wg sync.WaitGroup
+	next := make(chan int)
+	for j := 0; j < min(jobs, n); j++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				fn(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+}
+
+// reportUnusedConfig writes the unused entries of all configs to stderr, and returns how many there are.
+func reportUnusedConfig(configs map[string]*lint.ConfigurationFile) int {
+	all := make([]*lint.ConfigurationFile, 0, len(configs))
+	for _, config := range configs {
+		all = append(all, config)
+	}
+	unused := lint.UnusedConfigurations(all)
+	for _, u := range unused {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", u.File, u)
+	}
+	return len(unused)
+}
+
+// configuredRules returns all rules of the configurations in configs, including the rules they declare, in the
+// order of the configurations they were first configured in. Without configurations, it returns the built-in rules.
+func configuredRules(configs map[string]*lint.ConfigurationFile) []lint.Rule {
+	if len(configs) == 0 {
+		rules := lint.NewRuleSet()
+		return rules.Rules()
+	}
+	keys := make([]string, 0, len(configs))
+	for key := range configs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var rules []lint.Rule
+	seen := map[string]bool{}
+	for _, key := range keys {
+		// configurations are validated when loaded, so their rules can be declared
+		all, _ := configs[key].AllRules()
+		for _, r := range all.Rules() {
+			if !seen[r.Name()] {
+				seen[r.Name()] = true
+				rules = append(rules, r)
+			}
+		}
+	}
+	return rules
+}
+
+// reporter returns the reporter of the format set with --format, for output written to w. rules are the rules
+// dashboards were linted with, described by reports listing rules.
+func reporter(w io.Writer, rules []lint.Rule) lint.Reporter {
+	switch lintFormatFlag {
+	case "compact":
+		return lint.CompactReporter{Color: useColor(w), GroupBy: lint.GroupBy(lintGroupByFlag)}
+	case "json":
+		return lint.ReporterFunc(func(w io.Writer, rs *lint.ResultSet) error {
+			return rs.ReportJSON(w)
+		})
+	case "sarif":
+		return lint.ReporterFunc(func(w io.Writer, rs *lint.ResultSet) error {
+			return rs.ReportSARIF(w, rules)
+		})
+	case "junit":
+		return lint.ReporterFunc(func(w io.Writer, rs *lint.ResultSet) error {
+			return rs.ReportJUnit(w, lintJUnitWarningsFlag == "failure")
+		})
+	case "github":
+		return lint.ReporterFunc(func(w io.Writer, rs *lint.ResultSet) error {
+			return rs.ReportGitHubActions(w)
+		})
+	case "gitlab":
+		return lint.ReporterFunc(func(w io.Writer, rs *lint.ResultSet) error {
+			return rs.ReportGitLabCodeQuality(w)
+		})
+	}
+	return lint.TextReporter{Color: useColor(w), GroupBy: lint.GroupBy(lintGroupByFlag)}
+}
+
+// useColor returns whether output written to w is colored, as set with --color. In auto mode, it is if w is a
+// terminal, unless the NO_COLOR environment variable is set.
+func useColor(w io.Writer) bool {
+	switch lintColorFlag {
+	case "always":
+		return true
+	case "never":
+		return false
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+func lintFile(filename string, configs map[string]*lint.ConfigurationFile, jobs int, out io.Writer) (*lint.ResultSet, error) {
+	buf, err := os.ReadFile(filename)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("failed to read file %s: %v", filename, err)
+	}
+	return lintDashboard(filename, buf, configs, jobs, out)
+}
+
+// configsMu guards the configurations cached while dashboards are linted concurrently.
+var configsMu sync.Mutex
+
+// loadConfig returns the configuration for the dashboard read from filename, loading it if it isn't cached in
+// configs yet.
+func loadConfig(filename string, configs map[string]*lint.ConfigurationFile) (*lint.ConfigurationFile, error) {
+	configsMu.Lock()
+	defer configsMu.Unlock()
+
+	// if no config flag was passed, use the .lint files of the dashboard's directory and its parents
+	configKey := lintConfigFlag
+	if configKey == "" {
+		configKey = path.Dir(filename)
+	}
+	if config, ok := configs[configKey]; ok {
+		return config, nil
+	}
+
+	config := lint.NewConfigurationFile()
+	var err error
+	if lintConfigFlag != "" {
+		err = config.Load(lintConfigFlag)
+	} else {
+		err = config.LoadHierarchy(configKey)
 	}
-	c := conflate.New()
-	err = c.AddData(old, newBytes)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("failed to load lint config: %v", err)
 	}
-	b, err := c.MarshalJSON()
+	config.Verbose = lintVerboseFlag
+	config.Autofix = lintAutofixFlag
+	configs[configKey] = config
+	return config, nil
+}
+
+// lintDashboard lints a single dashboard read from filename, autofixing it if requested, and applies the
+// configuration found for it, running up to jobs rules at once. Configuration files are loaded at most once, and
+// cached in configs. With --dry-run, the fixes are written to out.
+func lintDashboard(filename string, buf []byte, configs map[string]*lint.ConfigurationFile, jobs int, out io.Writer) (*lint.ResultSet, error) {
+	config, err := loadConfig(filename, configs)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	allRules, err := config.AllRules()
+	if err != nil {
+		return nil, err
+	}
+	rules, err := allRules.Select(append(config.Selections(), flagRuleSelection())...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to select rules for dashboard %s: %v", filename, err)
+	}
+	linter := lint.NewLinter(
+		lint.WithRules(rules),
+		lint.WithConfiguration(config),
+		lint.WithFix(config.Autofix),
+		lint.WithJobs(jobs),
+	)
+	res, err := linter.LintReader(context.Background(), filename, bytes.NewReader(buf))
+	if err != nil {
+		return nil, err
+	}
+
+	// dashboards read from stdin are always written back out, fixed or not
+	if res.Fixes > 0 || config.Autofix && filename == "" {
+		fixed := res.Fixed
+		if fixed == nil {
+			fixed = buf
+		}
+		if err := write(fixed, filename, buf, out); err != nil {
+			return nil, err
+		}
+	}
+	return res.Results, nil
+}
+
+// write writes the fixed dashboard b back to filename. With --dry-run, a diff of the fixes is written to out
+// instead, or the whole fixed dashboard if it was read from stdin.
+func write(b []byte, filename string, old []byte, out io.Writer) error {
+	var err error
+	switch {
+	case !lintDryRunFlag:
+		return os.WriteFile(filename, b, 0600)
+	case filename == "":
+		_, err = out.Write(b)
+	default:
+		_, err = io.WriteString(out, lint.UnifiedDiff(old, b, filename, filename))
+	}
+	return err
+}
+
+// flagRuleSelection returns the rules selected with the --only, --disable and --enable flags.
+func flagRuleSelection() lint.RuleSelection {
+	return lint.RuleSelection{
+		Only:    lintOnlyFlag,
+		Disable: lintDisableFlag,
+		Enable:  lintEnableFlag,
 	}
-	json := strings.ReplaceAll(string(b), "\"options\": null,", "\"options\": [],")
+}
+
+var baselineCmd = &cobra.Command{
+	Use:   "baseline [dashboard.json|directory]...",
+	Short: "Record the current findings in a baseline file",
+	Long: `Records all errors, warnings and infos found in the given dashboards to a baseline file.
+
+Linting with --baseline then only reports findings which are not in the baseline, and entries of the
+baseline which were fixed. Run this command again to remove them from the baseline.`,
+	SilenceUsage: true,
+	Args:         cobra.ArbitraryArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		results, total, failed, err := lintArgs(args, map[string]*lint.ConfigurationFile{})
+		if err != nil {
+			return err
+		}
+		if failed > 0 {
+			return fmt.Errorf("failed to lint %d of %d dashboards, please see previous output", failed, total)
+		}
+
+		f, err := os.Create(baselineOutputFlag)
+		if err != nil {
+			return fmt.Errorf("failed to write baseline: %v", err)
+		}
+		defer f.Close()
+		baseline := lint.NewBaseline(results)
+		if err := baseline.Write(f); err != nil {
+			return fmt.Errorf("failed to write baseline: %v", err)
+		}
+		fmt.Fprintf(os.Stderr, "recorded %d findings of %d dashboards in %s\n", len(baseline.Entries), total, baselineOutputFlag)
+		return f.Close()
+	},
+}
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Work with configuration files",
+}
 
-	return os.WriteFile(filename, []byte(json), 0600)
+var configValidateCmd = &cobra.Command{
+	Use:   "validate [.lint]...",
+	Short: "Validate configuration files",
+	Long: `Validates configuration files, along with the configurations they extend, reporting unknown keys
+and rules, and invalid values along with the line they are on.
+
+Defaults to the .lint file of the current directory.`,
+	SilenceUsage: true,
+	Args:         cobra.ArbitraryArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			args = []string{".lint"}
+		}
+		invalid := 0
+		for _, path := range args {
+			// missing files are valid when linting, but not when validating them explicitly
+			if _, err := os.Stat(path); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				invalid++
+				continue
+			}
+			if err := lint.NewConfigurationFile().Load(path); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				invalid++
+				continue
+			}
+			fmt.Fprintf(os.Stdout, "%s is valid\n", path)
+		}
+		if invalid > 0 {
+			return fmt.Errorf("%d of %d configuration files are invalid, please see previous output", invalid, len(args))
+		}
+		return nil
+	},
 }
 
 var rulesCmd = &cobra.Command{
-	Use:          "rules",
-	Short:        "Print documentation about each lint rule.",
+	Use:   "rules",
+	Short: "Print documentation about each lint rule.",
+	Long: `Print documentation about each lint rule.
+
+When a configuration file, or any of --only, --disable or --enable are given, rules which are not
+run with them are marked as disabled.`,
 	SilenceUsage: true,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		rules := lint.NewRuleSet()
+		if rulesFormatFlag != "text" && rulesFormatFlag != "json" {
+			return fmt.Errorf("unknown output format '%s', must be one of text, json", rulesFormatFlag)
+		}
+		config := lint.NewConfigurationFile()
+		if err := config.Load(rulesConfigFlag); err != nil {
+			return fmt.Errorf("failed to load lint config: %v", err)
+		}
+		rules, err := config.AllRules()
+		if err != nil {
+			return err
+		}
+		selected, err := rules.Select(append(config.Selections(), flagRuleSelection())...)
+		if err != nil {
+			return fmt.Errorf("failed to select rules: %v", err)
+		}
+		enabled := map[string]bool{}
+		for _, rule := range selected.Rules() {
+			enabled[rule.Name()] = true
+		}
+
+		if rulesFormatFlag == "json" {
+			return reportRulesJSON(rules.Rules(), enabled)
+		}
 		for _, rule := range rules.Rules() {
-			fmt.Fprintf(os.Stdout, "* `%s` - %s\n", rule.Name(), rule.Description())
+			if enabled[rule.Name()] {
+				fmt.Fprintf(os.Stdout, "* `%s` - %s\n", rule.Name(), rule.Description())
+			} else {
+				fmt.Fprintf(os.Stdout, "* `%s` - %s (disabled)\n", rule.Name(), rule.Description())
+			}
 		}
 		return nil
 	},
 }
 
+// jsonRule is a rule as written by the rules command with --format json.
+type jsonRule struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	lint.RuleMetadata
+	Enabled bool `json:"enabled"`
+}
+
+func reportRulesJSON(rules []lint.Rule, enabled map[string]bool) error {
+	out := []jsonRule{}
+	for _, rule := range rules {
+		out = append(out, jsonRule{
+			Name:         rule.Name(),
+			Description:  rule.Description(),
+			RuleMetadata: rule.Metadata(),
+			Enabled:      enabled[rule.Name()],
+		})
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// addJobsFlag adds the flag setting how many dashboards and rules are linted at once to cmd.
+func addJobsFlag(cmd *cobra.Command) {
+	cmd.Flags().IntVarP(
+		&lintJobsFlag,
+		"jobs",
+		"j",
+		0,
+		"number of dashboards and rules to lint at once, 0 for the number of CPUs",
+	)
+}
+
+// addRuleSelectionFlags adds the flags selecting which rules are run to cmd.
+func addRuleSelectionFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVar(
+		&lintOnlyFlag,
+		"only",
+		nil,
+		"only run the rules matching these names or globs, e.g. target-*",
+	)
+	cmd.Flags().StringSliceVar(
+		&lintDisableFlag,
+		"disable",
+		nil,
+		"don't run the rules matching these names or globs",
+	)
+	cmd.Flags().StringSliceVar(
+		&lintEnableFlag,
+		"enable",
+		nil,
+		"run the rules matching these names or globs, even if disabled by --only, --disable or the configuration",
+	)
+}
+
 func init() {
 	rootCmd.AddCommand(lintCmd)
 	rootCmd.AddCommand(rulesCmd)
+	rootCmd.AddCommand(baselineCmd)
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configValidateCmd)
 	lintCmd.Flags().BoolVar(
 		&lintStrictFlag,
 		"strict",
 		false,
 		"fail upon linting error or warning",
 	)
+	lintCmd.Flags().IntVar(
+		&lintMaxWarningsFlag,
+		"max-warnings",
+		-1,
+		"fail if there are more than this many warnings, -1 to allow any number",
+	)
 	lintCmd.Flags().BoolVar(
 		&lintVerboseFlag,
 		"verbose",
@@ -148,6 +616,12 @@ func init() {
 		false,
 		"automatically fix problems if possible",
 	)
+	lintCmd.Flags().BoolVar(
+		&lintDryRunFlag,
+		"dry-run",
+		false,
+		"with --fix, print a diff of the fixes instead of writing them, or the fixed dashboard when reading from stdin",
+	)
 	lintCmd.Flags().StringVarP(
 		&lintConfigFlag,
 		"config",
@@ -161,6 +635,102 @@ func init() {
 		false,
 		"read from stdin",
 	)
+	lintCmd.Flags().StringVarP(
+		&lintFormatFlag,
+		"format",
+		"f",
+		"text",
+		"output format, one of text, compact, json, sarif, junit, github, gitlab",
+	)
+	lintCmd.Flags().StringVar(
+		&lintColorFlag,
+		"color",
+		"auto",
+		"when to color the text and compact formats, one of auto, always, never; auto colors terminals unless NO_COLOR is set",
+	)
+	lintCmd.Flags().StringVar(
+		&lintGroupByFlag,
+		"group-by",
+		"rule",
+		"what to group the results of the text and compact formats by, one of rule, dashboard, file",
+	)
+	lintCmd.Flags().StringVar(
+		&lintJUnitWarningsFlag,
+		"junit-warnings",
+		"failure",
+		"how warnings are reported in the junit format, one of failure, skipped",
+	)
+	lintCmd.Flags().StringSliceVar(
+		&lintIncludeFlag,
+		"include",
+		[]string{"*.json"},
+		"glob matching the names of dashboard files to lint when walking directories",
+	)
+	lintCmd.Flags().StringSliceVar(
+		&lintExcludeFlag,
+		"exclude",
+		nil,
+		"glob matching file or directory names, or paths relative to the walked directory, to skip when walking directories",
+	)
+	lintCmd.Flags().StringVar(
+		&lintBaselineFlag,
+		"baseline",
+		"",
+		"path to a baseline file written by the baseline command, only findings not recorded in it are reported",
+	)
+	lintCmd.Flags().BoolVar(
+		&lintReportUnusedConfigFlag,
+		"report-unused-config",
+		false,
+		"report configuration entries which matched nothing, and rules which don't exist, failing with --strict",
+	)
+	addJobsFlag(lintCmd)
+	addRuleSelectionFlags(lintCmd)
+
+	baselineCmd.Flags().StringVarP(
+		&baselineOutputFlag,
+		"output",
+		"o",
+		".lint-baseline.json",
+		"path to write the baseline file to",
+	)
+	baselineCmd.Flags().StringVarP(
+		&lintConfigFlag,
+		"config",
+		"c",
+		"",
+		"path to a configuration file",
+	)
+	baselineCmd.Flags().StringSliceVar(
+		&lintIncludeFlag,
+		"include",
+		[]string{"*.json"},
+		"glob matching the names of dashboard files to lint when walking directories",
+	)
+	baselineCmd.Flags().StringSliceVar(
+		&lintExcludeFlag,
+		"exclude",
+		nil,
+		"glob matching file or directory names, or paths relative to the walked directory, to skip when walking directories",
+	)
+	addJobsFlag(baselineCmd)
+	addRuleSelectionFlags(baselineCmd)
+
+	rulesCmd.Flags().StringVarP(
+		&rulesConfigFlag,
+		"config",
+		"c",
+		".lint",
+		"path to a configuration file",
+	)
+	rulesCmd.Flags().StringVarP(
+		&rulesFormatFlag,
+		"format",
+		"f",
+		"text",
+		"output format, one of text, json",
+	)
+	addRuleSelectionFlags(rulesCmd)
 }
 
 var rootCmd = &cobra.Command{
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitJobs(t *testing.T) {
+	for _, tc := range []struct {
+		jobs, n           int
+		dashboards, rules int
+	}{
+		{jobs: 8, n: 100, dashboards: 8, rules: 1},
+		{jobs: 8, n: 1, dashboards: 1, rules: 8},
+		{jobs: 8, n: 3, dashboards: 3, rules: 2},
+		{jobs: 1, n: 10, dashboards: 1, rules: 1},
+		{jobs: 4, n: 0, dashboards: 1, rules: 4},
+	} {
+		t.Run(fmt.Sprintf("%d jobs for %d dashboards", tc.jobs, tc.n), func(t *testing.T) {
+			dashboards, rules := splitJobs(tc.jobs, tc.n)
+			require.Equal(t, tc.dashboards, dashboards)
+			require.Equal(t, tc.rules, rules)
+			require.LessOrEqual(t, dashboards*rules, tc.jobs, "should not run more than --jobs at once")
+		})
+	}
+}